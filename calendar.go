@@ -0,0 +1,52 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/darkit/cron/internal/parser"
+)
+
+// HolidayCalendar 节假日日历，决定营业日语法（BD3、BDL、@businessday(N)）跳过哪些日期。
+// 周六、周日始终视为非营业日；date 为当天零点，实现方应只比较年月日。
+type HolidayCalendar interface {
+	IsHoliday(date time.Time) bool
+}
+
+// WithHolidayCalendar 设置调度器使用的节假日日历
+// 未设置时营业日仅排除周末
+func WithHolidayCalendar(calendar HolidayCalendar) Option {
+	return func(c *Cron) {
+		c.calendar = calendar
+	}
+}
+
+// StaticHolidayCalendar 基于固定日期集合的节假日日历
+type StaticHolidayCalendar struct {
+	days map[string]struct{}
+}
+
+// NewStaticHolidayCalendar 使用给定日期创建节假日日历，仅比较年月日
+func NewStaticHolidayCalendar(dates ...time.Time) *StaticHolidayCalendar {
+	c := &StaticHolidayCalendar{days: make(map[string]struct{}, len(dates))}
+	for _, date := range dates {
+		c.days[date.Format(time.DateOnly)] = struct{}{}
+	}
+	return c
+}
+
+// IsHoliday 实现 HolidayCalendar 接口
+func (c *StaticHolidayCalendar) IsHoliday(date time.Time) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.days[date.Format(time.DateOnly)]
+	return ok
+}
+
+// bindCalendar 将节假日日历绑定到解析结果，未设置日历时原样返回
+func bindCalendar(schedule parser.Schedule, calendar HolidayCalendar) parser.Schedule {
+	if calendar == nil {
+		return schedule
+	}
+	return parser.WithHolidayCalendar(schedule, calendar)
+}
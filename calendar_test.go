@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStaticHolidayCalendar(t *testing.T) {
+	cal := NewStaticHolidayCalendar(time.Date(2030, 3, 1, 15, 0, 0, 0, time.UTC))
+
+	if !cal.IsHoliday(time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected 2030-03-01 to be a holiday")
+	}
+	if cal.IsHoliday(time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected 2030-03-04 not to be a holiday")
+	}
+
+	var nilCal *StaticHolidayCalendar
+	if nilCal.IsHoliday(time.Now()) {
+		t.Fatal("nil calendar should not report holidays")
+	}
+}
+
+func TestBusinessDayScheduleUsesHolidayCalendar(t *testing.T) {
+	startAt := time.Date(2030, 2, 27, 0, 0, 0, 0, time.Local)
+	handler := func(ctx context.Context) {}
+
+	plain := New(WithLogger(&NoOpLogger{}))
+	if err := plain.Schedule("bd1", "0 0 BD1 * *", handler, JobOptions{StartAt: startAt}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	next, err := plain.NextRun("bd1")
+	if err != nil {
+		t.Fatalf("next run failed: %v", err)
+	}
+	// 2030-03-01 为周五，无节假日时即为首个营业日
+	if want := time.Date(2030, 3, 1, 0, 0, 0, 0, time.Local); !next.Equal(want) {
+		t.Fatalf("NextRun = %v; want %v", next, want)
+	}
+
+	cal := NewStaticHolidayCalendar(time.Date(2030, 3, 1, 0, 0, 0, 0, time.Local))
+	c := New(WithLogger(&NoOpLogger{}), WithHolidayCalendar(cal))
+	if err := c.Schedule("bd1", "@businessday(1)", handler, JobOptions{StartAt: startAt}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	next, err = c.NextRun("bd1")
+	if err != nil {
+		t.Fatalf("next run failed: %v", err)
+	}
+	if want := time.Date(2030, 3, 4, 0, 0, 0, 0, time.Local); !next.Equal(want) {
+		t.Fatalf("NextRun = %v; want %v", next, want)
+	}
+
+	// Update 重新解析后仍需保留日历绑定
+	if err := c.Update("bd1", "0 0 BD1 * *", JobOptions{StartAt: startAt}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	next, _ = c.NextRun("bd1")
+	if want := time.Date(2030, 3, 4, 0, 0, 0, 0, time.Local); !next.Equal(want) {
+		t.Fatalf("NextRun after update = %v; want %v", next, want)
+	}
+}
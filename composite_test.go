@@ -0,0 +1,43 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestScheduleCompositeSpec 测试 Schedule / Update 接受组合表达式
+func TestScheduleCompositeSpec(t *testing.T) {
+	tokyo := loadTestLocation(t, "Asia/Tokyo")
+	handler := func(ctx context.Context) {}
+
+	c := New(WithLogger(&NoOpLogger{}))
+	spec := "*/15 * * * * & * 9-17 * * 1-5 | 0 23 * * *"
+	if err := c.Schedule("composite", spec, handler, JobOptions{Location: tokyo}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	info, ok := c.GetTask("composite")
+	if !ok || info.Schedule != spec || info.TimeZone != "Asia/Tokyo" {
+		t.Fatalf("unexpected task info: %+v", info)
+	}
+	next, _ := c.NextRun("composite")
+	local := next.In(tokyo)
+	businessHours := local.Weekday() >= time.Monday && local.Weekday() <= time.Friday &&
+		local.Hour() >= 9 && local.Hour() <= 17 && local.Minute()%15 == 0
+	if !businessHours && !(local.Hour() == 23 && local.Minute() == 0) {
+		t.Fatalf("next run %v does not match composite spec", local)
+	}
+
+	if err := c.Update("composite", "0 8 * * * | 0 20 * * *"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	next, _ = c.NextRun("composite")
+	if local := next.In(tokyo); local.Minute() != 0 || (local.Hour() != 8 && local.Hour() != 20) {
+		t.Fatalf("expected 08:00 or 20:00 Asia/Tokyo after update, got %v", local)
+	}
+
+	if err := c.Update("composite", "0 8 * * * | bogus"); err == nil {
+		t.Fatal("expected invalid composite spec to be rejected")
+	}
+}
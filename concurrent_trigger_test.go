@@ -127,7 +127,7 @@ func TestManualConcurrentExecution(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			scheduler.executeTask(runner)
+			scheduler.executeTask(runner, TriggerManual, time.Time{})
 		}()
 	}
 
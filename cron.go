@@ -3,11 +3,13 @@ package cron
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/darkit/cron/history"
+	"github.com/darkit/cron/internal/parser"
 )
 
 // 常用的 cron 表达式
@@ -45,6 +47,17 @@ const (
 	MisfireCatchUp MisfirePolicy = "catchup" // 尝试追赶，最多补若干次
 )
 
+// DSTPolicy 定义夏令时切换处理策略，重叠与空隙两类策略可按位组合，
+// 如 DSTRunOnceInOverlap | DSTShiftGapForward。零值沿用默认行为（重叠执行两次、空隙跳过）。
+type DSTPolicy uint8
+
+const (
+	DSTRunBoth          = DSTPolicy(parser.DSTRunBoth)          // 重叠时段两次均执行
+	DSTRunOnceInOverlap = DSTPolicy(parser.DSTRunOnceInOverlap) // 重叠时段仅执行第一次
+	DSTSkipGap          = DSTPolicy(parser.DSTSkipGap)          // 跳过落在空隙中的触发点
+	DSTShiftGapForward  = DSTPolicy(parser.DSTShiftGapForward)  // 空隙中的触发点按空隙长度顺延
+)
+
 // JobOptions 任务配置选项
 type JobOptions struct {
 	Timeout       time.Duration     // 任务超时时间
@@ -60,20 +73,45 @@ type JobOptions struct {
 	StartAt       time.Time         // 首次执行时间，零值表示沿用默认首次调度行为
 	MaxRuns       int               // 最大计划执行次数，0 表示不限次数
 	Labels        map[string]string // 任务标签元数据
+	DSTPolicy     DSTPolicy         // 夏令时切换处理策略，零值沿用默认行为
+	Location      *time.Location    // 任务时区，nil 表示沿用 TZ= 前缀或调度器默认时区
+	EndAt         time.Time         // 截止时间，之后不再触发并自动移除任务，零值表示不限
+	ActiveWindows []ActiveWindow    // 有效时段，时段外的触发点被跳过，为空表示不限
+
+	ExpectSuccessWithin time.Duration // 成功 SLA：超过该时长未成功执行时标记为超期并发出 TaskOverdue 事件，0 表示不检查
+}
+
+// ActiveWindow 任务的有效时段，按挂钟时间计算，如每天 08:00-18:00 或工作日 22:00-次日 06:00
+type ActiveWindow struct {
+	Start    time.Duration  // 开始时刻，距当天零点的时长，如 8*time.Hour
+	End      time.Duration  // 结束时刻（不含），不大于 Start 时表示跨零点
+	Weekdays []time.Weekday // 生效的星期，为空表示每天；跨零点时段按开始当天判断
+	Location *time.Location // 时段所在时区，nil 表示沿用任务时区
 }
 
 // EventHook 任务事件回调
 type EventHook func(Event)
 
-// Event 任务事件数据
+// Event 任务事件数据，Type 区分事件类型，各类型适用的字段见 EventType 说明
 type Event struct {
-	TaskID   string
-	Start    time.Time
-	End      time.Time
-	Success  bool
-	Error    string
-	Retries  int
-	Duration time.Duration
+	Type          EventType
+	Time          time.Time         // 事件发生时间
+	TaskID        string            // 任务 ID，调度器级事件为空
+	Labels        map[string]string // 任务标签快照
+	ExecutionID   string            // 执行 ID，执行相关事件有效，与历史记录 ID 一致
+	Trigger       TriggerSource     // 触发来源，执行相关事件有效
+	Start         time.Time
+	End           time.Time
+	Success       bool
+	Error         string
+	Retries       int
+	Duration      time.Duration
+	ScheduledTime time.Time     // 计划触发时间，RunNow 手动触发时为零值
+	Lag           time.Duration // 实际开始相对计划时间的延迟
+	Attempt       int           // 尝试序号（从 1 开始），AttemptFailed / RetryScheduled 事件有效
+	RetryDelay    time.Duration // 距下次尝试的间隔，RetryScheduled 事件有效
+	PauseUntil    time.Time     // 自动恢复时间，AutoPaused 事件有效
+	LastSuccess   time.Time     // 最近一次成功时间，零值表示从未成功，TaskOverdue 事件有效
 }
 
 // Logger 定义日志接口
@@ -97,6 +135,17 @@ func WithLogger(logger Logger) Option {
 	}
 }
 
+// WithSlogLogger 使用 *slog.Logger 输出结构化日志，任务 ID、执行 ID、尝试序号、耗时与错误
+// 分别以 task_id / execution_id / attempt / duration / error 属性输出
+func WithSlogLogger(logger *slog.Logger) Option {
+	return func(c *Cron) {
+		if logger == nil {
+			return
+		}
+		c.logger = NewSlogLogger(logger)
+	}
+}
+
 // WithContext 设置调度器的根上下文，用于生命周期管理
 // 当上下文被取消时，调度器将停止调度新任务，并向所有正在执行的任务发送取消信号
 func WithContext(ctx context.Context) Option {
@@ -122,6 +171,14 @@ func WithHistoryRecorder(recorder history.Recorder) Option {
 	}
 }
 
+// WithLocation 设置调度器默认时区
+// 仅作用于既未使用 TZ= 前缀、也未设置 JobOptions.Location 的任务
+func WithLocation(loc *time.Location) Option {
+	return func(c *Cron) {
+		c.location = loc
+	}
+}
+
 // WithEventHook 设置任务事件回调
 func WithEventHook(hook EventHook) Option {
 	return func(c *Cron) {
@@ -129,6 +186,54 @@ func WithEventHook(hook EventHook) Option {
 	}
 }
 
+// WithLagWarnThreshold 设置调度延迟告警阈值：按计划触发的执行开始时间晚于计划时间超过 threshold 时，
+// 通过 Logger 输出警告，便于发现主机过载或 MaxConcurrent 瓶颈。0 表示不告警（默认）。
+func WithLagWarnThreshold(threshold time.Duration) Option {
+	return func(c *Cron) {
+		c.lagWarnThreshold = max(threshold, 0)
+	}
+}
+
+// WithTracer 设置执行追踪器：每次执行与每次尝试各对应一个 Span，
+// 尝试 Span 的上下文会传递给任务处理函数。nil 表示不追踪（默认）。
+func WithTracer(tracer Tracer) Option {
+	return func(c *Cron) {
+		c.tracer = tracer
+	}
+}
+
+// WithOverdueCheckInterval 设置成功 SLA（JobOptions.ExpectSuccessWithin）的检查间隔，
+// 默认 DefaultOverdueCheckInterval。间隔越短，超期发现越及时。
+func WithOverdueCheckInterval(interval time.Duration) Option {
+	return func(c *Cron) {
+		if interval > 0 {
+			c.overdueCheckInterval = interval
+		}
+	}
+}
+
+// WithLeakDetection 启用协程泄漏检测：每次执行的协程带有 task_id / execution_id pprof 标签，
+// 任务派生的协程会继承该标签；执行结束 grace 后仍带有该执行标签的协程计为泄漏，
+// 累计到 Stats.LeakedGoroutines。grace <= 0 时使用 DefaultLeakGracePeriod。
+// 每次检查需采集一次 goroutine profile，高频任务请适当增大 grace 以合并检查。
+func WithLeakDetection(grace time.Duration) Option {
+	return func(c *Cron) {
+		if grace <= 0 {
+			grace = DefaultLeakGracePeriod
+		}
+		c.leakGrace = grace
+	}
+}
+
+// WithResourceSampling 启用执行资源采样：每个任务每 every 次执行采样一次（1 表示每次），
+// 记录不含重试等待的执行耗时与堆分配字节数，写入 Stats 与历史记录的 Resources。
+// 堆分配量是进程级指标的差值，仅在采样期间没有其他任务执行时记录。every <= 0 表示不采样（默认）。
+func WithResourceSampling(every int) Option {
+	return func(c *Cron) {
+		c.resourceSampleEvery = max(every, 0)
+	}
+}
+
 // Cron 是一个极简的定时任务调度器
 type Cron struct {
 	scheduler    *scheduler
@@ -142,7 +247,20 @@ type Cron struct {
 	rootContext  context.Context  // 根上下文，用于生命周期管理
 	recorder     history.Recorder // 历史记录器（可选）
 	eventHook    EventHook
+	calendar     HolidayCalendar // 营业日语法使用的节假日日历（可选）
+	location     *time.Location  // 默认时区（可选）
 	watcherStop  chan struct{}
+
+	lagWarnThreshold time.Duration // 调度延迟告警阈值，0 表示不告警
+	tracer           Tracer        // 执行追踪（可选）
+
+	overdueCheckInterval time.Duration // 成功 SLA 检查间隔
+	leakGrace            time.Duration // 泄漏检测宽限期，0 表示不检测
+	resourceSampleEvery  int           // 资源采样间隔（执行次数），0 表示不采样
+
+	parserOptions  ParseOption       // 表达式解析选项（可选）
+	descriptors    map[string]string // 自定义描述符（可选）
+	descriptorErrs []error           // 注册时被拒绝的描述符，New 中输出告警
 }
 
 // New 创建一个新的定时任务调度器
@@ -152,6 +270,8 @@ func New(opts ...Option) *Cron {
 		logger:      defaultLog,
 		startTime:   time.Now(),
 		rootContext: context.Background(), // 默认使用 Background
+
+		overdueCheckInterval: DefaultOverdueCheckInterval,
 	}
 
 	// 应用选项
@@ -176,6 +296,20 @@ func New(opts ...Option) *Cron {
 	c.scheduler.panicHandler = c.panicHandler
 	c.scheduler.recorder = c.recorder
 	c.scheduler.eventHook = c.eventHook
+	c.scheduler.calendar = c.calendar
+	c.scheduler.location = c.location
+	c.scheduler.parserOptions = c.parserOptions
+	c.scheduler.descriptors = c.descriptors
+	c.scheduler.lagWarnThreshold = c.lagWarnThreshold
+	c.scheduler.tracer = c.tracer
+	c.scheduler.overdueCheckInterval = c.overdueCheckInterval
+	if c.leakGrace > 0 {
+		c.scheduler.leaks = newLeakDetector(c.leakGrace, c.scheduler.reportLeak)
+	}
+	if c.resourceSampleEvery > 0 {
+		c.scheduler.resources = newResourceSampler(c.resourceSampleEvery)
+	}
+	c.validateDescriptors()
 
 	return c
 }
@@ -243,6 +377,35 @@ func normalizeJobOptions(opts JobOptions) (JobOptions, error) {
 	if opts.MaxRuns < 0 {
 		return JobOptions{}, fmt.Errorf("max runs cannot be negative")
 	}
+	if opts.ExpectSuccessWithin < 0 {
+		return JobOptions{}, fmt.Errorf("expect success within cannot be negative")
+	}
+	if !opts.EndAt.IsZero() && !opts.StartAt.IsZero() && !opts.EndAt.After(opts.StartAt) {
+		return JobOptions{}, fmt.Errorf("end at must be after start at")
+	}
+	for i, window := range opts.ActiveWindows {
+		if window.Start < 0 || window.Start >= 24*time.Hour || window.End < 0 || window.End > 24*time.Hour {
+			return JobOptions{}, fmt.Errorf("active window %d must be within 00:00-24:00", i)
+		}
+		if window.Start == window.End {
+			return JobOptions{}, fmt.Errorf("active window %d cannot be empty", i)
+		}
+		for _, day := range window.Weekdays {
+			if day < time.Sunday || day > time.Saturday {
+				return JobOptions{}, fmt.Errorf("active window %d has invalid weekday %d", i, day)
+			}
+		}
+	}
+
+	if opts.DSTPolicy&^(DSTRunBoth|DSTRunOnceInOverlap|DSTSkipGap|DSTShiftGapForward) != 0 {
+		return JobOptions{}, fmt.Errorf("invalid dst policy %d", opts.DSTPolicy)
+	}
+	if opts.DSTPolicy&DSTRunBoth != 0 && opts.DSTPolicy&DSTRunOnceInOverlap != 0 {
+		return JobOptions{}, fmt.Errorf("dst policies run-both and run-once-in-overlap are mutually exclusive")
+	}
+	if opts.DSTPolicy&DSTSkipGap != 0 && opts.DSTPolicy&DSTShiftGapForward != 0 {
+		return JobOptions{}, fmt.Errorf("dst policies skip-gap and shift-gap-forward are mutually exclusive")
+	}
 
 	switch opts.MisfirePolicy {
 	case "":
@@ -296,6 +459,9 @@ func (c *Cron) Schedule(id, schedule string, handler func(ctx context.Context),
 		return err
 	}
 
+	var pending *Event
+	defer func() { c.emitPending(pending) }() // 在释放 c.mu 之后分发事件
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.closed {
@@ -330,8 +496,11 @@ func (c *Cron) Schedule(id, schedule string, handler func(ctx context.Context),
 	// 任务添加成功后再写监控，避免脏状态
 	if c.monitor != nil {
 		c.monitor.addTask(normalizedID, normalizedSchedule, createdAt, task.Labels, string(task.Options.MisfirePolicy))
+		c.monitor.setTimeZone(normalizedID, c.scheduler.timeZone(normalizedID))
+		c.monitor.setExpectSuccessWithin(normalizedID, task.Options.ExpectSuccessWithin)
 	}
 
+	pending = &Event{Type: EventTaskAdded, TaskID: normalizedID, Labels: cloneLabels(task.Labels)}
 	return nil
 }
 
@@ -346,6 +515,9 @@ func (c *Cron) ScheduleJob(id, schedule string, job Job, opts ...JobOptions) err
 		return err
 	}
 
+	var pending *Event
+	defer func() { c.emitPending(pending) }() // 在释放 c.mu 之后分发事件
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.closed {
@@ -380,8 +552,11 @@ func (c *Cron) ScheduleJob(id, schedule string, job Job, opts ...JobOptions) err
 	// 任务添加成功后再写监控，避免脏状态
 	if c.monitor != nil {
 		c.monitor.addTask(normalizedID, normalizedSchedule, createdAt, task.Labels, string(task.Options.MisfirePolicy))
+		c.monitor.setTimeZone(normalizedID, c.scheduler.timeZone(normalizedID))
+		c.monitor.setExpectSuccessWithin(normalizedID, task.Options.ExpectSuccessWithin)
 	}
 
+	pending = &Event{Type: EventTaskAdded, TaskID: normalizedID, Labels: cloneLabels(task.Labels)}
 	return nil
 }
 
@@ -539,13 +714,17 @@ func (c *Cron) Remove(id string) error {
 		return err
 	}
 
+	var pending *Event
+	defer func() { c.emitPending(pending) }() // 在释放 c.mu 之后分发事件
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.closed {
 		return fmt.Errorf("scheduler is closed")
 	}
 
-	if err := c.scheduler.removeTask(normalizedID); err != nil {
+	labels, err := c.scheduler.removeTask(normalizedID)
+	if err != nil {
 		return err
 	}
 
@@ -554,11 +733,15 @@ func (c *Cron) Remove(id string) error {
 		c.monitor.removeTask(normalizedID)
 	}
 
+	pending = &Event{Type: EventTaskRemoved, TaskID: normalizedID, Labels: labels}
 	return nil
 }
 
 // Start 启动调度器
 func (c *Cron) Start() error {
+	var pending *Event
+	defer func() { c.emitPending(pending) }() // 在释放 c.mu 之后分发事件
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.closed {
@@ -583,9 +766,17 @@ func (c *Cron) Start() error {
 	c.watcherStop = make(chan struct{})
 	go c.contextWatcher(c.watcherStop)
 
+	pending = &Event{Type: EventSchedulerStarted}
 	return nil
 }
 
+// emitPending 分发持有 c.mu 期间产生的事件，须在释放锁之后调用，避免钩子回调 Cron 时死锁
+func (c *Cron) emitPending(ev *Event) {
+	if ev != nil {
+		c.scheduler.emit(*ev)
+	}
+}
+
 // contextWatcher 监听根上下文的取消信号
 func (c *Cron) contextWatcher(stopCh <-chan struct{}) {
 	if c.rootContext == nil {
@@ -657,6 +848,10 @@ func (c *Cron) Close() error {
 	c.mu.Unlock()
 
 	c.stopInternal(5*time.Second, "Closing cron scheduler")
+	c.scheduler.events.close()
+	if c.scheduler.leaks != nil {
+		c.scheduler.leaks.stop()
+	}
 
 	c.mu.Lock()
 	recorder := c.recorder
@@ -668,9 +863,7 @@ func (c *Cron) Close() error {
 
 	if recorder != nil {
 		if err := recorder.Close(); err != nil {
-			if c.logger != nil {
-				c.logger.Warnf("Failed to close history recorder: %v", err)
-			}
+			logAttrs(context.Background(), c.logger, slog.LevelWarn, "failed to close history recorder", errorAttr(err))
 			return err
 		}
 	}
@@ -720,6 +913,7 @@ func (c *Cron) RunNow(id string) error {
 }
 
 // Update 更新任务的调度表达式及可选配置
+// 未传入 JobOptions 时沿用当前配置；新表达式未带 TZ= 前缀时沿用任务当前生效的时区
 func (c *Cron) Update(id, schedule string, opts ...JobOptions) error {
 	normalizedID, normalizedSchedule, err := normalizeTaskInputs(id, schedule)
 	if err != nil {
@@ -811,6 +1005,7 @@ type TaskInfo struct {
 	Labels        map[string]string // 元数据标签
 	NextRun       time.Time         // 下次执行时间
 	RemainingRuns int               // 剩余计划执行次数，-1 表示无限制
+	TimeZone      string            // 生效时区名称
 	IsPaused      bool              // 是否暂停
 	IsRunning     bool              // 是否正在运行
 	CreatedAt     time.Time         // 创建时间
@@ -912,6 +1107,20 @@ func (c *Cron) QueryHistory(filter history.RecordFilter) ([]*history.ExecutionRe
 	return c.recorder.Query(filter)
 }
 
+// IterateHistory 按开始时间倒序逐条遍历执行历史记录，不会一次性加载全部记录。
+// fn 收到的游标放入 filter.Cursor 可从该记录之后继续遍历；记录器未实现 history.RecordIterator 时
+// 返回 history.ErrIterationUnsupported
+func (c *Cron) IterateHistory(ctx context.Context, filter history.RecordFilter, fn func(record *history.ExecutionRecord, cursor string) error) error {
+	if c.recorder == nil {
+		return fmt.Errorf("history recorder is not enabled")
+	}
+	iterator, ok := c.recorder.(history.RecordIterator)
+	if !ok {
+		return history.ErrIterationUnsupported
+	}
+	return iterator.Iterate(ctx, filter, fn)
+}
+
 // CountHistory 统计历史记录数量
 func (c *Cron) CountHistory(filter history.RecordFilter) (int, error) {
 	if c.recorder == nil {
@@ -1,7 +1,9 @@
 package dashboard
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -134,6 +136,26 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, stats)
 }
 
+// Healthz 存活探针：后台调度循环停滞时返回 503，响应体为 cron.HealthReport
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	report := h.cron.Health()
+	status := http.StatusOK
+	if !report.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	h.writeJSON(w, status, report)
+}
+
+// Readyz 就绪探针：调度器未运行或历史记录持续写入失败等情况下返回 503，响应体为 cron.HealthReport
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.cron.Health()
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	h.writeJSON(w, status, report)
+}
+
 // GetHistory 获取历史记录
 func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
 	// 解析查询参数
@@ -195,48 +217,88 @@ func (h *Handler) GetHistory(w http.ResponseWriter, r *http.Request) {
 		filter.Offset = offset
 	}
 
-	// 查询历史记录
-	recordsPtr, err := h.cron.QueryHistory(filter)
-	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Failed to query history: "+err.Error())
+	filter.Cursor = r.URL.Query().Get("cursor")
+	if filter.Cursor != "" && r.URL.Query().Has("offset") {
+		h.writeError(w, http.StatusBadRequest, "offset cannot be combined with cursor")
 		return
 	}
 
-	// 转换为非指针切片
-	records := make([]history.ExecutionRecord, len(recordsPtr))
-	for i, rec := range recordsPtr {
-		records[i] = *rec
+	// 查询历史记录
+	records, nextCursor, err := h.queryHistoryPage(r.Context(), filter)
+	if errors.Is(err, history.ErrInvalidCursor) {
+		h.writeError(w, http.StatusBadRequest, "Invalid cursor")
+		return
+	}
+	if errors.Is(err, history.ErrIterationUnsupported) {
+		h.writeError(w, http.StatusBadRequest, "Cursor pagination is not supported by the history storage")
+		return
 	}
-
-	// 统计总数
-	totalCount, err := h.cron.CountHistory(history.RecordFilter{
-		TaskID:      filter.TaskID,
-		SuccessOnly: filter.SuccessOnly,
-		FailedOnly:  filter.FailedOnly,
-		StartTime:   filter.StartTime,
-		EndTime:     filter.EndTime,
-	})
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Failed to count history: "+err.Error())
+		h.writeError(w, http.StatusInternalServerError, "Failed to query history: "+err.Error())
 		return
 	}
 
-	// 计算分页信息
-	pageSize := filter.Limit
-	totalPages := (totalCount + pageSize - 1) / pageSize
-	page := filter.Offset/pageSize + 1
-
 	response := HistoryResponse{
 		Records:    records,
-		Total:      totalCount,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
+		PageSize:   filter.Limit,
+		NextCursor: nextCursor,
+	}
+	// 游标分页不统计总数与页码，每页只读取所需记录
+	if filter.Cursor == "" {
+		totalCount, err := h.cron.CountHistory(history.RecordFilter{
+			TaskID:      filter.TaskID,
+			SuccessOnly: filter.SuccessOnly,
+			FailedOnly:  filter.FailedOnly,
+			StartTime:   filter.StartTime,
+			EndTime:     filter.EndTime,
+		})
+		if err != nil {
+			h.writeError(w, http.StatusInternalServerError, "Failed to count history: "+err.Error())
+			return
+		}
+		totalPages := (totalCount + filter.Limit - 1) / filter.Limit
+		response.Total = &totalCount
+		response.TotalPages = &totalPages
+		response.Page = filter.Offset/filter.Limit + 1
 	}
 
 	h.writeJSON(w, http.StatusOK, response)
 }
 
+// queryHistoryPage 通过游标遍历读取一页记录，多读一条以判断是否还有下一页；
+// 存储不支持遍历且未指定游标时回退到 QueryHistory，此时不返回下一页游标
+func (h *Handler) queryHistoryPage(ctx context.Context, filter history.RecordFilter) ([]history.ExecutionRecord, string, error) {
+	records := make([]history.ExecutionRecord, 0, filter.Limit)
+	cursors := make([]string, 0, filter.Limit)
+	pageFilter := filter
+	pageFilter.Limit = filter.Limit + 1
+	err := h.cron.IterateHistory(ctx, pageFilter, func(record *history.ExecutionRecord, cursor string) error {
+		records = append(records, *record)
+		cursors = append(cursors, cursor)
+		return nil
+	})
+	if errors.Is(err, history.ErrIterationUnsupported) && filter.Cursor == "" {
+		recordsPtr, err := h.cron.QueryHistory(filter)
+		if err != nil {
+			return nil, "", err
+		}
+		// 转换为非指针切片
+		records = records[:0]
+		for _, rec := range recordsPtr {
+			records = append(records, *rec)
+		}
+		return records, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(records) > filter.Limit {
+		return records[:filter.Limit], cursors[filter.Limit-1], nil
+	}
+	return records, "", nil
+}
+
 // RemoveTask 移除任务
 func (h *Handler) RemoveTask(w http.ResponseWriter, r *http.Request) {
 	taskID := taskIDFromRequest(r)
@@ -320,6 +382,23 @@ func (h *Handler) UpdateTaskSchedule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.cron.Update(taskID, req.Schedule); err != nil {
+		var parseErr *cron.ParseError
+		if errors.As(err, &parseErr) {
+			h.writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error:   http.StatusText(http.StatusBadRequest),
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+				ParseError: &ParseErrorDetail{
+					Spec:   parseErr.Spec,
+					Field:  parseErr.Field,
+					Index:  parseErr.Index,
+					Token:  parseErr.Token,
+					Offset: parseErr.Offset,
+					Reason: string(parseErr.Reason),
+				},
+			})
+			return
+		}
 		h.writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -342,21 +421,35 @@ func (h *Handler) getTaskInfo(taskID string) *TaskInfo {
 	}
 
 	info := &TaskInfo{
-		ID:            taskID,
-		Schedule:      stats.Schedule,
-		NextRun:       nextRun,
-		IsRunning:     stats.IsRunning,
-		RunCount:      stats.RunCount,
-		SuccessCount:  stats.SuccessCount,
-		FailCount:     stats.FailCount,
-		RetryCount:    stats.RetryCount,
-		SkippedCount:  stats.SkippedCount,
-		PauseUntil:    stats.PauseUntil,
-		MisfirePolicy: stats.MisfirePolicy,
-		LastRunTime:   stats.LastRun,
-		Labels:        stats.Labels,
-		LastError:     stats.LastError,
-		Descriptions:  make(map[string]string),
+		ID:                taskID,
+		Schedule:          stats.Schedule,
+		NextRun:           nextRun,
+		IsRunning:         stats.IsRunning,
+		RunCount:          stats.RunCount,
+		SuccessCount:      stats.SuccessCount,
+		FailCount:         stats.FailCount,
+		RetryCount:        stats.RetryCount,
+		SkippedCount:      stats.SkippedCount,
+		PauseUntil:        stats.PauseUntil,
+		MisfirePolicy:     stats.MisfirePolicy,
+		TimeZone:          stats.TimeZone,
+		LastRunTime:       stats.LastRun,
+		Labels:            stats.Labels,
+		LastError:         stats.LastError,
+		Descriptions:      make(map[string]string),
+		AvgDuration:       "0s",
+		P50Duration:       h.formatDuration(stats.P50Duration),
+		P90Duration:       h.formatDuration(stats.P90Duration),
+		P99Duration:       h.formatDuration(stats.P99Duration),
+		RecentAvgDuration: h.formatDuration(stats.RecentAvgDuration),
+		LastSuccess:       stats.LastSuccess,
+		Overdue:           stats.Overdue,
+	}
+	if stats.ExpectSuccessWithin > 0 {
+		info.ExpectSuccessWithin = h.formatDuration(stats.ExpectSuccessWithin)
+	}
+	if stats.RunCount > 0 {
+		info.AvgDuration = h.formatDuration(stats.TotalDuration / time.Duration(stats.RunCount))
 	}
 
 	if stats.HasLastResult {
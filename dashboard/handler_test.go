@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -340,6 +342,70 @@ func TestGetHistoryWithFilter(t *testing.T) {
 	}
 }
 
+// TestGetHistoryCursor 测试通过 nextCursor 依次翻页取完全部记录，非法游标返回 400
+func TestGetHistoryCursor(t *testing.T) {
+	storage := history.NewMemoryStorage(0, 0)
+	recorder, err := history.NewHistoryRecorder(storage)
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	c := cron.New(cron.WithHistoryRecorder(recorder))
+	t.Cleanup(func() { c.Close() })
+	handler := NewHandler(c)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		start := base.Add(time.Duration(i) * time.Minute)
+		if err := storage.Save(&history.ExecutionRecord{ID: fmt.Sprintf("r%d", i), TaskID: "task", StartTime: start}); err != nil {
+			t.Fatalf("Failed to save record: %v", err)
+		}
+	}
+
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("Expected 3 pages, got more: %v", ids)
+		}
+		req := httptest.NewRequest("GET", "/api/history?limit=2&cursor="+url.QueryEscape(cursor), nil)
+		w := httptest.NewRecorder()
+		handler.GetHistory(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response HistoryResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if cursor == "" {
+			if response.Page != 1 || response.Total == nil || *response.Total != 5 || response.TotalPages == nil || *response.TotalPages != 3 {
+				t.Fatalf("Expected page 1 of 3 with total 5 on the first page, got %+v", response)
+			}
+		} else if response.Page != 0 || response.Total != nil || response.TotalPages != nil {
+			t.Fatalf("Expected page and totals to be omitted in cursor mode, got %+v", response)
+		}
+		for _, record := range response.Records {
+			ids = append(ids, record.ID)
+		}
+		if response.NextCursor == "" {
+			break
+		}
+		cursor = response.NextCursor
+	}
+	if got := strings.Join(ids, ","); got != "r4,r3,r2,r1,r0" {
+		t.Fatalf("Unexpected records across pages: %s", got)
+	}
+
+	for _, query := range []string{"cursor=invalid!", "offset=0&cursor=" + url.QueryEscape(cursor)} {
+		req := httptest.NewRequest("GET", "/api/history?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.GetHistory(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
 // TestRemoveTask 测试移除任务
 func TestRemoveTask(t *testing.T) {
 	c := setupTestCron(t)
@@ -395,3 +461,152 @@ func TestGetHistoryRejectsInvalidParams(t *testing.T) {
 		}
 	}
 }
+
+// TestUpdateTaskScheduleKeepsTimeZone 测试重新输入表达式时保留任务时区
+func TestUpdateTaskScheduleKeepsTimeZone(t *testing.T) {
+	c := cron.New()
+	if err := c.Schedule("tz-task", "TZ=Asia/Tokyo 0 9 * * *", func(ctx context.Context) {}); err != nil {
+		t.Skipf("time zone unavailable: %v", err)
+	}
+	handler := NewHandler(c)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/tasks/tz-task/schedule", strings.NewReader(`{"schedule":"0 10 * * *"}`))
+	req.SetPathValue("id", "tz-task")
+	w := httptest.NewRecorder()
+	handler.UpdateTaskSchedule(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/tasks/tz-task", nil)
+	req.SetPathValue("id", "tz-task")
+	w = httptest.NewRecorder()
+	handler.GetTask(w, req)
+
+	var info TaskInfo
+	if err := json.NewDecoder(w.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if info.TimeZone != "Asia/Tokyo" {
+		t.Fatalf("Expected timeZone Asia/Tokyo, got %q", info.TimeZone)
+	}
+}
+
+// TestUpdateTaskScheduleParseError 测试表达式解析失败时返回出错字段与位置
+func TestUpdateTaskScheduleParseError(t *testing.T) {
+	c := cron.New()
+	if err := c.Schedule("parse-task", "0 9 * * *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("Failed to schedule task: %v", err)
+	}
+	handler := NewHandler(c)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/tasks/parse-task/schedule", strings.NewReader(`{"schedule":"0 9 32 * *"}`))
+	req.SetPathValue("id", "parse-task")
+	w := httptest.NewRecorder()
+	handler.UpdateTaskSchedule(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	detail := resp.ParseError
+	if detail == nil {
+		t.Fatalf("Expected parseError detail, got %+v", resp)
+	}
+	if detail.Field != "day-of-month" || detail.Index != 2 || detail.Token != "32" || detail.Offset != 4 || detail.Reason != "out_of_range" {
+		t.Fatalf("Unexpected parse error detail: %+v", *detail)
+	}
+}
+
+// TestGetTaskReportsDurationPercentiles 测试任务详情包含平均时长、分位数与近期平均
+func TestGetTaskReportsDurationPercentiles(t *testing.T) {
+	c := cron.New()
+	defer c.Stop()
+	handler := NewHandler(c)
+
+	done := make(chan struct{}, 1)
+	if err := c.Schedule("pct-task", "0 0 1 1 *", func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		done <- struct{}{}
+	}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.RunNow("pct-task"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+	<-done
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if stats, ok := c.GetStats("pct-task"); ok && stats.RunCount == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("run was not recorded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/pct-task", nil)
+	req.SetPathValue("id", "pct-task")
+	w := httptest.NewRecorder()
+	handler.GetTask(w, req)
+
+	var task TaskInfo
+	if err := json.NewDecoder(w.Body).Decode(&task); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	for name, value := range map[string]string{
+		"avgDuration":       task.AvgDuration,
+		"p50Duration":       task.P50Duration,
+		"p90Duration":       task.P90Duration,
+		"p99Duration":       task.P99Duration,
+		"recentAvgDuration": task.RecentAvgDuration,
+	} {
+		if value == "" || value == "0s" {
+			t.Errorf("expected %s to be reported, got %q", name, value)
+		}
+	}
+}
+
+// TestGetTaskReportsOverdue 测试超过成功 SLA 的任务在详情中标记为超期
+func TestGetTaskReportsOverdue(t *testing.T) {
+	c := cron.New(cron.WithOverdueCheckInterval(10 * time.Millisecond))
+	defer c.Stop()
+	handler := NewHandler(c)
+
+	if err := c.Schedule("sla-task", "0 0 1 1 *", func(ctx context.Context) {}, cron.JobOptions{ExpectSuccessWithin: 30 * time.Millisecond}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if stats, ok := c.GetStats("sla-task"); ok && stats.Overdue {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("task was not marked overdue")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/sla-task", nil)
+	req.SetPathValue("id", "sla-task")
+	w := httptest.NewRecorder()
+	handler.GetTask(w, req)
+
+	var task TaskInfo
+	if err := json.NewDecoder(w.Body).Decode(&task); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !task.Overdue || task.ExpectSuccessWithin == "" || !task.LastSuccess.IsZero() {
+		t.Fatalf("expected overdue task info, got %+v", task)
+	}
+}
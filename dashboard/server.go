@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/darkit/cron"
+	"github.com/darkit/cron/metrics"
 )
 
 //go:embed web/*
@@ -35,6 +36,13 @@ func WithAllowedOrigins(origins []string) ServerOption {
 	}
 }
 
+// WithMetricsLabels 设置 /metrics 额外导出的任务标签键，标签名为 label_<key>
+func WithMetricsLabels(keys ...string) ServerOption {
+	return func(s *Server) {
+		s.metricsLabels = append(s.metricsLabels, keys...)
+	}
+}
+
 // Server Dashboard 服务器
 type Server struct {
 	cron           *cron.Cron
@@ -44,6 +52,7 @@ type Server struct {
 	logger         *log.Logger
 	apiKey         string   // API Key 认证（空表示禁用）
 	allowedOrigins []string // 允许的 CORS 来源（空表示允许所有）
+	metricsLabels  []string // /metrics 导出的任务标签键
 }
 
 // WithLogger 设置 Dashboard 使用的标准库 logger。
@@ -107,6 +116,16 @@ func (s *Server) buildHandler() (http.Handler, error) {
 	}
 	rootMux.Handle("/api/", apiHandler)
 
+	var metricsHandler http.Handler = metrics.NewHandler(s.cron, metrics.WithTaskLabels(s.metricsLabels...))
+	if s.apiKey != "" {
+		metricsHandler = s.authMiddleware(metricsHandler)
+	}
+	rootMux.Handle("GET /metrics", metricsHandler)
+
+	// 探针供 kubelet 等调用，不经过 API Key 认证
+	rootMux.HandleFunc("GET /healthz", s.handler.Healthz)
+	rootMux.HandleFunc("GET /readyz", s.handler.Readyz)
+
 	webRoot, err := fs.Sub(webFS, "web")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get web root: %w", err)
@@ -666,3 +666,81 @@ func TestServerStartReturnsErrorOnPortConflict(t *testing.T) {
 		t.Fatal("expected port conflict error")
 	}
 }
+
+// TestServerMetricsEndpoint 测试 /metrics 输出 Prometheus 文本并受 API Key 保护
+func TestServerMetricsEndpoint(t *testing.T) {
+	c := cron.New()
+	defer c.Stop()
+	if err := c.Schedule("metrics-task", "@every 24h", func(ctx context.Context) {}, cron.JobOptions{
+		Labels: map[string]string{"team": "ops"},
+	}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	server := NewServer(c, ":0", WithAPIKey("secret-key"), WithMetricsLabels("team"))
+	ts := newDashboardHTTPServer(t, server)
+
+	resp, err := ts.Client().Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without api key, got %d", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret-key")
+	resp, err = ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", resp.StatusCode, body)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `cron_task_runs_total{task_id="metrics-task",label_team="ops"} 0`) {
+		t.Fatalf("unexpected metrics output:\n%s", body)
+	}
+}
+
+// TestServerHealthEndpoints 测试探针无需 API Key，就绪探针随调度器启停切换状态码
+func TestServerHealthEndpoints(t *testing.T) {
+	c := cron.New()
+	defer c.Stop()
+
+	server := NewServer(c, ":0", WithAPIKey("secret-key"))
+	ts := newDashboardHTTPServer(t, server)
+
+	probe := func(path string) (int, cron.HealthReport) {
+		t.Helper()
+		resp, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var report cron.HealthReport
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			t.Fatalf("failed to decode health report: %v", err)
+		}
+		return resp.StatusCode, report
+	}
+
+	if status, report := probe("/healthz"); status != http.StatusOK || !report.Healthy {
+		t.Fatalf("expected healthy, got %d %+v", status, report)
+	}
+	if status, report := probe("/readyz"); status != http.StatusServiceUnavailable || report.Ready || len(report.Problems) == 0 {
+		t.Fatalf("expected not ready before start, got %d %+v", status, report)
+	}
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if status, report := probe("/readyz"); status != http.StatusOK || !report.Ready || !report.Running {
+		t.Fatalf("expected ready after start, got %d %+v", status, report)
+	}
+}
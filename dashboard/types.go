@@ -8,23 +8,32 @@ import (
 
 // TaskInfo 任务信息
 type TaskInfo struct {
-	ID            string            `json:"id"`            // 任务ID
-	Schedule      string            `json:"schedule"`      // 调度表达式
-	NextRun       time.Time         `json:"nextRun"`       // 下次执行时间
-	IsRunning     bool              `json:"isRunning"`     // 是否正在运行
-	RunCount      int64             `json:"runCount"`      // 运行次数
-	SuccessCount  int64             `json:"successCount"`  // 成功次数
-	FailCount     int64             `json:"failCount"`     // 失败次数
-	RetryCount    int64             `json:"retryCount"`    // 重试次数
-	SkippedCount  int64             `json:"skippedCount"`  // 因并发限制被跳过次数
-	PauseUntil    time.Time         `json:"pauseUntil"`    // 暂停到期时间（熔断或手动）
-	MisfirePolicy string            `json:"misfirePolicy"` // Misfire 策略
-	LastRunTime   time.Time         `json:"lastRunTime"`   // 上次运行时间
-	LastRunStatus string            `json:"lastRunStatus"` // 上次运行状态
-	LastError     string            `json:"lastError"`     // 最后一次错误
-	Descriptions  map[string]string `json:"descriptions"`  // 任务描述信息
-	Labels        map[string]string `json:"labels"`        // 任务标签
-	AvgDuration   string            `json:"avgDuration"`   // 平均执行时长
+	ID                string            `json:"id"`                // 任务ID
+	Schedule          string            `json:"schedule"`          // 调度表达式
+	NextRun           time.Time         `json:"nextRun"`           // 下次执行时间
+	IsRunning         bool              `json:"isRunning"`         // 是否正在运行
+	RunCount          int64             `json:"runCount"`          // 运行次数
+	SuccessCount      int64             `json:"successCount"`      // 成功次数
+	FailCount         int64             `json:"failCount"`         // 失败次数
+	RetryCount        int64             `json:"retryCount"`        // 重试次数
+	SkippedCount      int64             `json:"skippedCount"`      // 因并发限制被跳过次数
+	PauseUntil        time.Time         `json:"pauseUntil"`        // 暂停到期时间（熔断或手动）
+	MisfirePolicy     string            `json:"misfirePolicy"`     // Misfire 策略
+	TimeZone          string            `json:"timeZone"`          // 生效时区
+	LastRunTime       time.Time         `json:"lastRunTime"`       // 上次运行时间
+	LastRunStatus     string            `json:"lastRunStatus"`     // 上次运行状态
+	LastError         string            `json:"lastError"`         // 最后一次错误
+	Descriptions      map[string]string `json:"descriptions"`      // 任务描述信息
+	Labels            map[string]string `json:"labels"`            // 任务标签
+	AvgDuration       string            `json:"avgDuration"`       // 平均执行时长
+	P50Duration       string            `json:"p50Duration"`       // 执行时长中位数
+	P90Duration       string            `json:"p90Duration"`       // 执行时长 90 分位
+	P99Duration       string            `json:"p99Duration"`       // 执行时长 99 分位
+	RecentAvgDuration string            `json:"recentAvgDuration"` // 最近 64 次执行的平均时长
+
+	LastSuccess         time.Time `json:"lastSuccess"`                   // 最近一次成功时间
+	ExpectSuccessWithin string    `json:"expectSuccessWithin,omitempty"` // 成功 SLA，未设置时为空
+	Overdue             bool      `json:"overdue"`                       // 超过 SLA 未成功
 }
 
 // StatsInfo 统计信息
@@ -50,15 +59,17 @@ type HistoryFilter struct {
 	EndTime     *time.Time `json:"endTime,omitempty"`
 	Limit       int        `json:"limit,omitempty"`
 	Offset      int        `json:"offset,omitempty"`
+	Cursor      string     `json:"cursor,omitempty"`
 }
 
 // HistoryResponse 历史记录响应
 type HistoryResponse struct {
 	Records    []history.ExecutionRecord `json:"records"`
-	Total      int                       `json:"total"`
-	Page       int                       `json:"page"`
+	Total      *int                      `json:"total,omitempty"` // 游标分页时省略，避免每页统计全部记录
+	Page       int                       `json:"page,omitempty"`
 	PageSize   int                       `json:"pageSize"`
-	TotalPages int                       `json:"totalPages"`
+	TotalPages *int                      `json:"totalPages,omitempty"` // 游标分页时省略
+	NextCursor string                    `json:"nextCursor,omitempty"`
 }
 
 // ErrorResponse 错误响应
@@ -66,6 +77,19 @@ type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
 	Code    int    `json:"code"`
+
+	// ParseError 调度表达式解析失败时的位置信息
+	ParseError *ParseErrorDetail `json:"parseError,omitempty"`
+}
+
+// ParseErrorDetail 表达式解析错误详情，供前端高亮出错字段
+type ParseErrorDetail struct {
+	Spec   string `json:"spec"`
+	Field  string `json:"field,omitempty"`
+	Index  int    `json:"index"`
+	Token  string `json:"token,omitempty"`
+	Offset int    `json:"offset"`
+	Reason string `json:"reason"`
 }
 
 // SuccessResponse 成功响应
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestJobOptionsDSTPolicyValidation(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	handler := func(ctx context.Context) {}
+
+	invalid := []DSTPolicy{
+		DSTRunBoth | DSTRunOnceInOverlap,
+		DSTSkipGap | DSTShiftGapForward,
+		DSTPolicy(1 << 7),
+	}
+	for _, policy := range invalid {
+		if err := c.Schedule("dst-invalid", EveryDay, handler, JobOptions{DSTPolicy: policy}); err == nil {
+			t.Fatalf("expected policy %b to be rejected", policy)
+		}
+	}
+
+	if err := c.Schedule("dst-valid", EveryDay, handler, JobOptions{DSTPolicy: DSTRunOnceInOverlap | DSTShiftGapForward}); err != nil {
+		t.Fatalf("expected combined policy to be accepted: %v", err)
+	}
+}
+
+func TestDSTPolicyAppliedToNextRun(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone unavailable: %v", err)
+	}
+
+	// 2030-03-31 为柏林夏令时切换日，02:30 不存在
+	startAt := time.Date(2030, 3, 31, 0, 0, 0, 0, loc)
+	spec := "TZ=Europe/Berlin 0 30 2 * * *"
+	handler := func(ctx context.Context) {}
+
+	c := New(WithLogger(&NoOpLogger{}))
+	if err := c.Schedule("skip", spec, handler, JobOptions{StartAt: startAt}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("shift", spec, handler, JobOptions{StartAt: startAt, DSTPolicy: DSTShiftGapForward}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	next, _ := c.NextRun("skip")
+	if want := time.Date(2030, 4, 1, 2, 30, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("skip NextRun = %v; want %v", next, want)
+	}
+	next, _ = c.NextRun("shift")
+	if want := time.Date(2030, 3, 31, 3, 30, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("shift NextRun = %v; want %v", next, want)
+	}
+
+	// 未传 JobOptions 的 Update 应保留原有策略
+	if err := c.Update("shift", spec); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	info, _ := c.GetTask("shift")
+	if info.Options.DSTPolicy != DSTShiftGapForward {
+		t.Fatalf("DSTPolicy = %b; want %b", info.Options.DSTPolicy, DSTShiftGapForward)
+	}
+}
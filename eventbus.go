@@ -0,0 +1,151 @@
+package cron
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscribeBuffer Subscribe 未指定缓冲区大小时使用的默认值
+const DefaultSubscribeBuffer = 64
+
+// EventFilter 事件订阅过滤条件，各条件同时满足时投递，零值表示接收全部事件
+type EventFilter struct {
+	TaskIDs []string          // 任务 ID，为空表示不限；设置后不接收调度器级事件
+	Labels  map[string]string // 标签选择器，事件标签须包含全部键值对
+	Types   []EventType       // 事件类型，为空表示不限
+}
+
+// Match 判断事件是否满足过滤条件
+func (f EventFilter) Match(ev Event) bool {
+	if len(f.TaskIDs) > 0 && !slices.Contains(f.TaskIDs, ev.TaskID) {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, ev.Type) {
+		return false
+	}
+	for key, value := range f.Labels {
+		if actual, ok := ev.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// subscriber 单个订阅者，缓冲区满时丢弃新事件并计数
+type subscriber struct {
+	ch      chan Event
+	filter  EventFilter
+	dropped atomic.Int64
+}
+
+// eventBus 多订阅者事件总线，投递不阻塞事件产生方
+type eventBus struct {
+	mu     sync.RWMutex
+	subs   map[<-chan Event]*subscriber
+	count  atomic.Int32
+	closed bool
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[<-chan Event]*subscriber)}
+}
+
+// active 是否存在订阅者，用于跳过无人接收时的事件构造开销
+func (b *eventBus) active() bool {
+	return b != nil && b.count.Load() > 0
+}
+
+// subscribe 注册订阅者，总线已关闭时返回已关闭的 channel
+func (b *eventBus) subscribe(filter EventFilter, buffer int) (<-chan Event, func()) {
+	if buffer <= 0 {
+		buffer = DefaultSubscribeBuffer
+	}
+	filter.TaskIDs = slices.Clone(filter.TaskIDs)
+	filter.Types = slices.Clone(filter.Types)
+	filter.Labels = cloneLabels(filter.Labels)
+	sub := &subscriber{ch: make(chan Event, buffer), filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	b.subs[sub.ch] = sub
+	b.count.Add(1)
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() { b.unsubscribe(sub.ch) })
+	}
+}
+
+// unsubscribe 移除订阅者并关闭其 channel
+func (b *eventBus) unsubscribe(ch <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub, ok := b.subs[ch]
+	if !ok {
+		return
+	}
+	delete(b.subs, ch)
+	b.count.Add(-1)
+	close(sub.ch)
+}
+
+// publish 向匹配的订阅者投递事件，缓冲区满时丢弃并计数
+func (b *eventBus) publish(ev Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, sub := range b.subs {
+		if !sub.filter.Match(ev) {
+			continue
+		}
+		delivered := ev
+		delivered.Labels = cloneLabels(ev.Labels)
+		select {
+		case sub.ch <- delivered:
+		default:
+			sub.dropped.Add(1)
+		}
+	}
+}
+
+// dropped 返回订阅者因缓冲区满而丢弃的事件数
+func (b *eventBus) dropped(ch <-chan Event) (int64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	sub, ok := b.subs[ch]
+	if !ok {
+		return 0, false
+	}
+	return sub.dropped.Load(), true
+}
+
+// close 关闭总线及全部订阅者 channel
+func (b *eventBus) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for ch, sub := range b.subs {
+		delete(b.subs, ch)
+		close(sub.ch)
+	}
+	b.count.Store(0)
+}
+
+// Subscribe 订阅事件流，返回只读 channel 与取消函数。
+// 每个订阅者拥有独立缓冲区（buffer <= 0 时为 DefaultSubscribeBuffer），投递不阻塞任务执行，
+// 缓冲区满时丢弃新事件并计入 DroppedEvents。取消订阅或 Close 后 channel 被关闭。
+func (c *Cron) Subscribe(filter EventFilter, buffer int) (<-chan Event, func()) {
+	return c.scheduler.events.subscribe(filter, buffer)
+}
+
+// DroppedEvents 返回订阅者因缓冲区满而丢弃的事件数，订阅已取消时返回 false
+func (c *Cron) DroppedEvents(ch <-chan Event) (int64, bool) {
+	return c.scheduler.events.dropped(ch)
+}
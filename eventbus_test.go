@@ -0,0 +1,153 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// drain 读取 channel 中已缓冲的全部事件
+func drain(ch <-chan Event) []Event {
+	var events []Event
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return events
+			}
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
+
+// TestEventFilterMatch 测试任务 ID、标签选择器与事件类型过滤
+func TestEventFilterMatch(t *testing.T) {
+	ev := Event{Type: EventTaskFinished, TaskID: "a", Labels: map[string]string{"team": "ops", "env": "prod"}}
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   bool
+	}{
+		{"零值", EventFilter{}, true},
+		{"任务匹配", EventFilter{TaskIDs: []string{"b", "a"}}, true},
+		{"任务不匹配", EventFilter{TaskIDs: []string{"b"}}, false},
+		{"标签匹配", EventFilter{Labels: map[string]string{"team": "ops"}}, true},
+		{"标签值不同", EventFilter{Labels: map[string]string{"team": "dev"}}, false},
+		{"标签缺失", EventFilter{Labels: map[string]string{"region": ""}}, false},
+		{"类型匹配", EventFilter{Types: []EventType{EventTaskFinished}}, true},
+		{"类型不匹配", EventFilter{Types: []EventType{EventTaskStarted}}, false},
+		{"组合条件", EventFilter{TaskIDs: []string{"a"}, Labels: map[string]string{"env": "prod"}, Types: []EventType{EventTaskFinished}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(ev); got != tt.want {
+				t.Fatalf("Match = %v; want %v", got, tt.want)
+			}
+		})
+	}
+	if (EventFilter{TaskIDs: []string{"a"}}).Match(Event{Type: EventSchedulerStarted}) {
+		t.Fatal("task filter should exclude scheduler events")
+	}
+}
+
+// TestSubscribeMultipleSubscribers 测试多个订阅者各自按过滤条件接收事件
+func TestSubscribeMultipleSubscribers(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	defer c.Close()
+
+	all, cancelAll := c.Subscribe(EventFilter{}, 0)
+	defer cancelAll()
+	finished, cancelFinished := c.Subscribe(EventFilter{Types: []EventType{EventTaskFinished}, Labels: map[string]string{"team": "ops"}}, 8)
+	defer cancelFinished()
+
+	if err := c.Schedule("ops", "0 0 1 1 *", func(ctx context.Context) {}, JobOptions{Labels: map[string]string{"team": "ops"}}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("dev", "0 0 1 1 *", func(ctx context.Context) {}, JobOptions{Labels: map[string]string{"team": "dev"}}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	for _, id := range []string{"ops", "dev"} {
+		if err := c.RunNow(id); err != nil {
+			t.Fatalf("run now failed: %v", err)
+		}
+	}
+
+	got := drain(finished)
+	if len(got) != 1 || got[0].TaskID != "ops" || got[0].Type != EventTaskFinished {
+		t.Fatalf("filtered subscriber got %+v", got)
+	}
+	// 2 次注册 + 调度器启动 + 2 次执行各开始、结束
+	if events := drain(all); len(events) != 7 {
+		t.Fatalf("unfiltered subscriber got %d events: %+v", len(events), events)
+	}
+}
+
+// TestSubscribeDropsWhenFull 测试缓冲区满时不阻塞任务并累计丢弃计数
+func TestSubscribeDropsWhenFull(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	defer c.Close()
+
+	ch, cancel := c.Subscribe(EventFilter{Types: []EventType{EventTaskFinished}}, 1)
+	if err := c.Schedule("fast", "0 0 1 1 *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	start := time.Now()
+	for range 5 {
+		if err := c.RunNow("fast"); err != nil {
+			t.Fatalf("run now failed: %v", err)
+		}
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("slow subscriber should not block task execution")
+	}
+	if dropped, ok := c.DroppedEvents(ch); !ok || dropped != 4 {
+		t.Fatalf("dropped = %d, %v; want 4", dropped, ok)
+	}
+	if events := drain(ch); len(events) != 1 {
+		t.Fatalf("expected 1 buffered event, got %d", len(events))
+	}
+
+	cancel()
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Fatal("channel should be closed after cancel")
+	}
+	if _, ok := c.DroppedEvents(ch); ok {
+		t.Fatal("cancelled subscription should not report stats")
+	}
+}
+
+// TestSubscribeClosedOnClose 测试 Close 关闭全部订阅，之后订阅立即返回已关闭的 channel
+func TestSubscribeClosedOnClose(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	ch, cancel := c.Subscribe(EventFilter{Types: []EventType{EventSchedulerStopped}}, 4)
+	defer cancel()
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	events := drain(ch)
+	if len(events) != 1 || events[0].Type != EventSchedulerStopped {
+		t.Fatalf("expected stopped event before close, got %+v", events)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel should be closed after Close")
+	}
+
+	late, _ := c.Subscribe(EventFilter{}, 1)
+	if _, ok := <-late; ok {
+		t.Fatal("subscription after Close should be closed")
+	}
+}
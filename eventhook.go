@@ -1,5 +1,26 @@
 package cron
 
+// EventType 事件类型
+type EventType string
+
+const (
+	EventTaskStarted      EventType = "task_started"      // 执行开始，Start 有效
+	EventTaskFinished     EventType = "task_finished"     // 执行结束，End / Success / Error / Retries / Duration 有效
+	EventTaskAdded        EventType = "task_added"        // 任务注册
+	EventTaskRemoved      EventType = "task_removed"      // 任务被移除
+	EventTaskUpdated      EventType = "task_updated"      // 调度表达式或配置被更新
+	EventTaskPaused       EventType = "task_paused"       // 手动暂停
+	EventTaskResumed      EventType = "task_resumed"      // 手动恢复或自动暂停到期恢复
+	EventTaskAutoPaused   EventType = "task_auto_paused"  // 连续失败达到阈值自动暂停，PauseUntil 有效
+	EventTaskSkipped      EventType = "task_skipped"      // 因并发限制跳过本次触发
+	EventAttemptFailed    EventType = "attempt_failed"    // 单次尝试失败，Attempt / Error 有效
+	EventRetryScheduled   EventType = "retry_scheduled"   // 已安排重试，Attempt 为下次尝试序号，RetryDelay 有效
+	EventTaskExpired      EventType = "task_expired"      // 计划执行完毕或到达截止时间后自动移除
+	EventTaskOverdue      EventType = "task_overdue"      // 超过 ExpectSuccessWithin 未成功执行，LastSuccess 有效
+	EventSchedulerStarted EventType = "scheduler_started" // 调度器启动
+	EventSchedulerStopped EventType = "scheduler_stopped" // 调度器停止
+)
+
 // NewEventLoggerHook 返回简单的事件日志钩子，仅在任务结束时记录结果
 func NewEventLoggerHook(l Logger) EventHook {
 	if l == nil {
@@ -7,8 +28,8 @@ func NewEventLoggerHook(l Logger) EventHook {
 	}
 
 	return func(ev Event) {
-		if ev.End.IsZero() {
-			return // 开始事件不打日志，避免噪声
+		if ev.Type != EventTaskFinished && (ev.Type != "" || ev.End.IsZero()) {
+			return // 仅记录执行结束事件，避免噪声
 		}
 		if ev.Success {
 			l.Infof("task %s done, duration=%s, retries=%d", ev.TaskID, ev.Duration, ev.Retries)
@@ -1,6 +1,9 @@
 package cron
 
 import (
+	"context"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 )
@@ -15,3 +18,153 @@ func TestNewEventChannelHookNonBlockingWhenChannelFull(t *testing.T) {
 		t.Fatal("expected hook to be non-blocking when channel is full")
 	}
 }
+
+// eventLog 线程安全的事件收集器
+type eventLog struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (l *eventLog) hook(ev Event) {
+	l.mu.Lock()
+	l.events = append(l.events, ev)
+	l.mu.Unlock()
+}
+
+// types 返回指定任务（空字符串表示调度器级事件）的事件类型序列
+func (l *eventLog) types(taskID string) []EventType {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var types []EventType
+	for _, ev := range l.events {
+		if ev.TaskID == taskID {
+			types = append(types, ev.Type)
+		}
+	}
+	return types
+}
+
+func (l *eventLog) find(typ EventType) (Event, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, ev := range l.events {
+		if ev.Type == typ {
+			return ev, true
+		}
+	}
+	return Event{}, false
+}
+
+// TestLifecycleEvents 测试任务生命周期各阶段均发出带类型、标签与执行 ID 的事件
+func TestLifecycleEvents(t *testing.T) {
+	log := &eventLog{}
+	var c *Cron
+	c = New(WithLogger(&NoOpLogger{}), WithEventHook(func(ev Event) {
+		log.hook(ev)
+		if ev.TaskID != "" {
+			c.GetTask(ev.TaskID) // 钩子内回调调度器不应死锁
+		}
+	}))
+
+	opts := JobOptions{
+		MaxRetries:    1,
+		FailThreshold: 2,
+		PauseDuration: time.Hour,
+		Labels:        map[string]string{"team": "ops"},
+	}
+	if err := c.ScheduleJob("job", "0 0 1 1 *", &flakyJob{failures: 10}, opts); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.Update("job", "0 0 2 1 *"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if err := c.Pause("job"); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if err := c.Resume("job"); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if err := c.RunNow("job"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+	if err := c.Remove("job"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	c.Stop()
+
+	want := []EventType{
+		EventTaskAdded, EventTaskUpdated, EventTaskPaused, EventTaskResumed,
+		EventTaskStarted, EventAttemptFailed, EventRetryScheduled, EventAttemptFailed,
+		EventTaskAutoPaused, EventTaskFinished, EventTaskRemoved,
+	}
+	if got := log.types("job"); !slices.Equal(got, want) {
+		t.Fatalf("task events = %v; want %v", got, want)
+	}
+	if got := log.types(""); !slices.Equal(got, []EventType{EventSchedulerStarted, EventSchedulerStopped}) {
+		t.Fatalf("scheduler events = %v", got)
+	}
+
+	started, _ := log.find(EventTaskStarted)
+	if started.ExecutionID == "" || started.Trigger != TriggerManual || started.Labels["team"] != "ops" {
+		t.Fatalf("unexpected started event: %+v", started)
+	}
+	retry, _ := log.find(EventRetryScheduled)
+	if retry.ExecutionID != started.ExecutionID || retry.Attempt != 2 || retry.Error != "boom" {
+		t.Fatalf("unexpected retry event: %+v", retry)
+	}
+	paused, _ := log.find(EventTaskAutoPaused)
+	if paused.ExecutionID != started.ExecutionID || paused.PauseUntil.Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("unexpected auto paused event: %+v", paused)
+	}
+	removed, _ := log.find(EventTaskRemoved)
+	if removed.Labels["team"] != "ops" || removed.Time.IsZero() {
+		t.Fatalf("unexpected removed event: %+v", removed)
+	}
+}
+
+// TestSkippedAndExpiredEvents 测试并发限制跳过与自动过期事件
+func TestSkippedAndExpiredEvents(t *testing.T) {
+	log := &eventLog{}
+	s := newScheduler()
+	s.logger = &NoOpLogger{}
+	s.eventHook = log.hook
+
+	release := make(chan struct{})
+	task := &Task{ID: "busy", Schedule: "0 0 1 1 *", Handler: func(ctx context.Context) { <-release }, Options: JobOptions{
+		MaxConcurrent: 1,
+		Async:         true,
+	}}
+	if err := s.addTask(task); err != nil {
+		t.Fatalf("add task failed: %v", err)
+	}
+	runner := s.tasks[task.ID]
+	s.executeTask(runner, TriggerSchedule, time.Now())
+	s.executeTask(runner, TriggerSchedule, time.Now())
+	close(release)
+	s.execWG.Wait()
+
+	skipped, ok := log.find(EventTaskSkipped)
+	if !ok || skipped.TaskID != "busy" || skipped.ExecutionID == "" || skipped.Trigger != TriggerSchedule {
+		t.Fatalf("unexpected skipped event: %+v", skipped)
+	}
+
+	s.expireTask(task.ID)
+	if expired, ok := log.find(EventTaskExpired); !ok || expired.TaskID != "busy" {
+		t.Fatalf("expected expired event, got %+v", expired)
+	}
+}
+
+// TestEventLoggerHookOnlyLogsFinished 测试日志钩子只记录执行结束事件
+func TestEventLoggerHookOnlyLogsFinished(t *testing.T) {
+	logger := &logBuffer{}
+	hook := NewEventLoggerHook(logger)
+	hook(Event{Type: EventTaskStarted, TaskID: "a"})
+	hook(Event{Type: EventTaskAdded, TaskID: "a", End: time.Now()})
+	hook(Event{Type: EventTaskFinished, TaskID: "a", End: time.Now(), Success: true})
+	if len(logger.entries) != 1 || !logger.contains("task a done") {
+		t.Fatalf("unexpected log entries: %v", logger.entries)
+	}
+}
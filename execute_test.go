@@ -107,23 +107,22 @@ func TestExecuteWithTimeout_Panic(t *testing.T) {
 	}
 }
 
-// TestExecuteWithTimeout_GoroutineMonitoring 测试 goroutine 监控
+// TestExecuteWithTimeout_GoroutineMonitoring 测试启用泄漏检测时任务派生的协程继承执行标签
 func TestExecuteWithTimeout_GoroutineMonitoring(t *testing.T) {
 	s := newScheduler()
-	logEntries := &logBuffer{}
-	s.logger = logEntries
+	s.logger = &logBuffer{}
+	s.leaks = newLeakDetector(time.Second, s.reportLeak)
 
-	// 创建一个会泄漏 goroutine 的任务
-	// 我们启动多个 goroutine 以确保泄漏能被检测到
-	success, err := s.executeWithTimeout("test-task", context.Background(), func() error {
+	release := make(chan struct{})
+	defer close(release)
+	ctx := withExecutionLog(context.Background(), "test-task-exec", 1)
+	success, err := s.executeWithTimeout("test-task", ctx, func() error {
 		// 启动多个不会立即退出的 goroutine
 		for range 5 {
 			go func() {
-				time.Sleep(5 * time.Second)
+				<-release
 			}()
 		}
-		// 等待确保所有 goroutine 都已启动
-		time.Sleep(100 * time.Millisecond)
 		return nil
 	}, nil, s.logger)
 
@@ -134,10 +133,17 @@ func TestExecuteWithTimeout_GoroutineMonitoring(t *testing.T) {
 		t.Errorf("Expected no error, got: %v", err)
 	}
 
-	// 检查是否记录了 goroutine 泄漏警告
-	// 由于我们启动了5个额外的 goroutine，应该会触发警告
-	if !logEntries.contains("may have goroutine leak") {
-		t.Error("Expected goroutine leak warning")
+	// 执行协程已退出，仅剩 5 个派生协程带有该执行的标签
+	deadline := time.Now().Add(time.Second)
+	for {
+		leaked := labeledGoroutines(LogKeyExecutionID)["test-task-exec"]
+		if leaked == 5 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected 5 labelled goroutines, got: %d", leaked)
+		}
+		time.Sleep(10 * time.Millisecond)
 	}
 }
 
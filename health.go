@@ -0,0 +1,105 @@
+package cron
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/darkit/cron/history"
+)
+
+const (
+	// heartbeatStaleFactor 心跳超过检查间隔的该倍数未刷新、或任务到期超过该倍数仍未被调度循环处理，即视为停滞
+	heartbeatStaleFactor = 3
+	// recorderFailureThreshold 历史记录连续写入失败达到该次数即视为持续失败
+	recorderFailureThreshold = 3
+)
+
+// HealthReport 调度器健康报告。Healthy 用于存活探针，仅在后台检查循环或任务调度循环停滞时为 false；
+// Ready 用于就绪探针，调度器未运行、根上下文已结束、调度停滞或历史记录持续写入失败时为 false。
+// 超期任务只做报告，不影响两者。
+type HealthReport struct {
+	Healthy  bool      `json:"healthy"`            // 存活状态
+	Ready    bool      `json:"ready"`              // 就绪状态
+	Problems []string  `json:"problems,omitempty"` // 导致不健康或未就绪的原因
+	Time     time.Time `json:"time"`               // 报告生成时间
+
+	Running        bool                    `json:"running"`                 // 调度器是否运行中
+	Closed         bool                    `json:"closed"`                  // 调度器是否已关闭
+	ContextError   string                  `json:"context_error,omitempty"` // 根上下文已结束的原因
+	LastHeartbeat  time.Time               `json:"last_heartbeat"`          // 后台检查循环最近一次完成检查的时间
+	HeartbeatAge   time.Duration           `json:"heartbeat_age"`           // 距最近一次心跳的时长
+	HeartbeatStale bool                    `json:"heartbeat_stale"`         // 运行中且心跳超时
+	StalledTasks   []string                `json:"stalled_tasks,omitempty"` // 到期超过 3 个检查间隔仍未被调度循环处理的任务
+	TaskCount      int                     `json:"task_count"`              // 任务数
+	OverdueTasks   []string                `json:"overdue_tasks,omitempty"` // 超过成功 SLA 的任务
+	Recorder       *history.RecorderHealth `json:"recorder,omitempty"`      // 历史记录器写入状态，未配置或不支持时为 nil
+}
+
+// Health 返回调度器的健康报告，适合用作 Kubernetes 存活 / 就绪探针的数据源
+func (c *Cron) Health() HealthReport {
+	now := time.Now()
+	report := HealthReport{Time: now}
+
+	c.mu.RLock()
+	report.Running = c.running
+	report.Closed = c.closed
+	recorder := c.recorder
+	rootCtx := c.rootContext
+	c.mu.RUnlock()
+
+	if rootCtx != nil && rootCtx.Err() != nil {
+		report.ContextError = rootCtx.Err().Error()
+	}
+
+	if nanos := c.scheduler.heartbeat.Load(); nanos != 0 {
+		report.LastHeartbeat = time.Unix(0, nanos)
+		report.HeartbeatAge = now.Sub(report.LastHeartbeat)
+	}
+	staleAfter := heartbeatStaleFactor * c.scheduler.overdueCheckInterval
+	report.HeartbeatStale = report.Running && report.HeartbeatAge > staleAfter
+	if stalled := c.scheduler.stalled.Load(); stalled != nil && report.Running {
+		report.StalledTasks = *stalled
+	}
+
+	// 任务数以调度器任务表为准，监控统计可能残留已移除或过期的任务
+	allStats := c.GetAllStats()
+	c.scheduler.mu.RLock()
+	report.TaskCount = len(c.scheduler.tasks)
+	for id := range c.scheduler.tasks {
+		if stats, ok := allStats[id]; ok && stats.Overdue {
+			report.OverdueTasks = append(report.OverdueTasks, id)
+		}
+	}
+	c.scheduler.mu.RUnlock()
+	slices.Sort(report.OverdueTasks)
+
+	if reporter, ok := recorder.(history.HealthReporter); ok {
+		health := reporter.Health()
+		report.Recorder = &health
+	}
+
+	report.Healthy = true
+	if report.HeartbeatStale {
+		report.Healthy = false
+		report.Problems = append(report.Problems, fmt.Sprintf("scheduler heartbeat is stale (last %v ago)", report.HeartbeatAge.Truncate(time.Millisecond)))
+	}
+	if len(report.StalledTasks) > 0 {
+		report.Healthy = false
+		report.Problems = append(report.Problems, "task dispatch loops are stalled: "+strings.Join(report.StalledTasks, ", "))
+	}
+	if report.Closed {
+		report.Problems = append(report.Problems, "scheduler is closed")
+	} else if !report.Running {
+		report.Problems = append(report.Problems, "scheduler is not running")
+	}
+	if report.ContextError != "" {
+		report.Problems = append(report.Problems, "root context is done: "+report.ContextError)
+	}
+	if report.Recorder != nil && report.Recorder.ConsecutiveFailures >= recorderFailureThreshold {
+		report.Problems = append(report.Problems, fmt.Sprintf("history recorder failed %d writes in a row: %s", report.Recorder.ConsecutiveFailures, report.Recorder.LastError))
+	}
+	report.Ready = len(report.Problems) == 0
+	return report
+}
@@ -0,0 +1,167 @@
+package cron
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkit/cron/history"
+)
+
+// healthRecorder 报告固定写入状态的历史记录器
+type healthRecorder struct {
+	stubRecorder
+	health history.RecorderHealth
+}
+
+func (r *healthRecorder) Health() history.RecorderHealth { return r.health }
+
+// hasProblem 判断报告是否包含指定原因
+func hasProblem(report HealthReport, substr string) bool {
+	for _, problem := range report.Problems {
+		if strings.Contains(problem, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestHealthRunningState 测试未启动时未就绪但存活，启动后就绪并有心跳，停止后再次未就绪
+func TestHealthRunningState(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	defer c.Close()
+
+	if err := c.Schedule("task", "0 0 1 1 *", func(ctx context.Context) {}, JobOptions{ExpectSuccessWithin: time.Millisecond}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	report := c.Health()
+	if !report.Healthy || report.Ready || !hasProblem(report, "not running") {
+		t.Fatalf("stopped scheduler should be alive but not ready: %+v", report)
+	}
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	report = c.Health()
+	if !report.Healthy || !report.Ready || report.LastHeartbeat.IsZero() || report.TaskCount != 1 {
+		t.Fatalf("running scheduler should be ready: %+v", report)
+	}
+
+	c.monitor.checkOverdue(time.Now().Add(time.Second))
+	report = c.Health()
+	if !report.Ready || len(report.OverdueTasks) != 1 || report.OverdueTasks[0] != "task" {
+		t.Fatalf("overdue tasks should be reported without affecting readiness: %+v", report)
+	}
+
+	c.Stop()
+	if report = c.Health(); report.Ready || report.Running {
+		t.Fatalf("stopped scheduler should not be ready: %+v", report)
+	}
+}
+
+// TestHealthTaskCount 测试任务数与超期任务以调度器任务表为准，忽略监控中残留的统计
+func TestHealthTaskCount(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	defer c.Close()
+
+	if err := c.Schedule("task", "0 0 1 1 *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	c.monitor.addTask("ghost", "0 0 1 1 *", time.Now(), nil, "")
+	c.monitor.setExpectSuccessWithin("ghost", time.Millisecond)
+	c.monitor.checkOverdue(time.Now().Add(time.Second))
+
+	report := c.Health()
+	if report.TaskCount != 1 || len(report.OverdueTasks) != 0 {
+		t.Fatalf("stale monitor stats should be ignored: %+v", report)
+	}
+}
+
+// TestHealthStaleHeartbeat 测试心跳超时时存活与就绪均失败
+func TestHealthStaleHeartbeat(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}), WithOverdueCheckInterval(time.Hour))
+	defer c.Close()
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	c.scheduler.heartbeat.Store(time.Now().Add(-4 * time.Hour).UnixNano())
+	report := c.Health()
+	if report.Healthy || report.Ready || !report.HeartbeatStale || !hasProblem(report, "heartbeat") {
+		t.Fatalf("stale heartbeat should fail liveness: %+v", report)
+	}
+}
+
+// TestHealthStalledDispatchLoop 测试任务调度循环阻塞时存活失败，而后台检查循环的心跳仍在刷新
+func TestHealthStalledDispatchLoop(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}), WithOverdueCheckInterval(10*time.Millisecond))
+	defer c.Close()
+	if err := c.Schedule("stuck", "* * * * * *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	// 持有任务锁模拟死锁：计时器到期后调度循环无法继续
+	c.scheduler.mu.RLock()
+	runner := c.scheduler.tasks["stuck"]
+	c.scheduler.mu.RUnlock()
+	runner.mu.Lock()
+	locked := true
+	defer func() {
+		if locked {
+			runner.mu.Unlock()
+		}
+	}()
+
+	deadline := time.Now().Add(3 * time.Second)
+	report := c.Health()
+	for len(report.StalledTasks) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		report = c.Health()
+	}
+	if report.Healthy || report.HeartbeatStale || !hasProblem(report, "stalled: stuck") {
+		t.Fatalf("stalled dispatch loop should fail liveness: %+v", report)
+	}
+
+	runner.mu.Unlock()
+	locked = false
+	deadline = time.Now().Add(3 * time.Second)
+	for !report.Healthy && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		report = c.Health()
+	}
+	if !report.Healthy || len(report.StalledTasks) != 0 {
+		t.Fatalf("dispatch loop should recover after unlock: %+v", report)
+	}
+}
+
+// TestHealthContextAndRecorder 测试根上下文结束与历史记录持续写入失败时未就绪
+func TestHealthContextAndRecorder(t *testing.T) {
+	recorder := &healthRecorder{health: history.RecorderHealth{QueueLength: 7, ConsecutiveFailures: 2, LastError: "disk full"}}
+	c := New(WithLogger(&NoOpLogger{}), WithHistoryRecorder(recorder))
+	defer c.Close()
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	report := c.Health()
+	if !report.Ready || report.Recorder == nil || report.Recorder.QueueLength != 7 {
+		t.Fatalf("transient recorder failures should not fail readiness: %+v", report)
+	}
+	recorder.health.ConsecutiveFailures = recorderFailureThreshold
+	if report = c.Health(); report.Ready || !report.Healthy || !hasProblem(report, "disk full") {
+		t.Fatalf("persistent recorder failures should fail readiness: %+v", report)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c2 := New(WithLogger(&NoOpLogger{}), WithContext(ctx))
+	defer c2.Close()
+	if report = c2.Health(); report.ContextError == "" || report.Ready || report.Recorder != nil {
+		t.Fatalf("cancelled root context should be reported: %+v", report)
+	}
+}
@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	// histogramSubBuckets 每个 2 的幂区间细分的桶数，分位数相对误差不超过约 6%
+	histogramSubBuckets = 8
+	// histogramSubBucketBits log2(histogramSubBuckets)
+	histogramSubBucketBits = 3
+	// recentWindowSize 近期平均时长统计的执行次数
+	recentWindowSize = 64
+)
+
+// durationHistogram 有界的执行时长流式直方图（HDR 风格对数-线性分桶），精度为微秒。
+// 桶按需增长，最多约 60 × histogramSubBuckets 个，内存与执行次数无关。
+type durationHistogram struct {
+	counts []int64
+	total  int64
+	min    time.Duration
+	max    time.Duration
+
+	recent    [recentWindowSize]time.Duration // 最近执行时长的环形缓冲
+	recentLen int
+	recentPos int
+	recentSum time.Duration
+}
+
+// record 记录一次执行时长
+func (h *durationHistogram) record(d time.Duration) {
+	d = max(d, 0)
+	idx := histogramIndex(uint64(d / time.Microsecond))
+	if idx >= len(h.counts) {
+		grown := make([]int64, idx+1)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[idx]++
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total++
+
+	if h.recentLen == recentWindowSize {
+		h.recentSum -= h.recent[h.recentPos]
+	} else {
+		h.recentLen++
+	}
+	h.recent[h.recentPos] = d
+	h.recentSum += d
+	h.recentPos = (h.recentPos + 1) % recentWindowSize
+}
+
+// quantile 返回分位数 q（0~1）的近似值，取所在桶的中点并限制在观测到的最小、最大值之间；
+// 排名落在最后一次时返回精确的最大值
+func (h *durationHistogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := max(int64(math.Ceil(q*float64(h.total))), 1)
+	if rank >= h.total {
+		return h.max
+	}
+	var cumulative int64
+	for idx, count := range h.counts {
+		cumulative += count
+		if cumulative >= rank {
+			lower, width := histogramBucket(idx)
+			mid := time.Duration(lower+width/2) * time.Microsecond
+			return min(max(mid, h.min), h.max)
+		}
+	}
+	return h.max
+}
+
+// recentAverage 返回最近 recentWindowSize 次执行的平均时长
+func (h *durationHistogram) recentAverage() time.Duration {
+	if h.recentLen == 0 {
+		return 0
+	}
+	return h.recentSum / time.Duration(h.recentLen)
+}
+
+// histogramIndex 计算微秒值所在的桶：小于 histogramSubBuckets 的值逐一分桶，
+// 其余按 2 的幂区间再细分为 histogramSubBuckets 个等宽桶
+func histogramIndex(v uint64) int {
+	if v < histogramSubBuckets {
+		return int(v)
+	}
+	exp := bits.Len64(v) - 1
+	sub := int(v>>(exp-histogramSubBucketBits)) & (histogramSubBuckets - 1)
+	return (exp-histogramSubBucketBits+1)*histogramSubBuckets + sub
+}
+
+// histogramBucket 返回桶的下界与宽度（微秒），与 histogramIndex 互逆
+func histogramBucket(idx int) (lower, width uint64) {
+	if idx < histogramSubBuckets {
+		return uint64(idx), 1
+	}
+	exp := idx/histogramSubBuckets + histogramSubBucketBits - 1
+	sub := uint64(idx % histogramSubBuckets)
+	shift := exp - histogramSubBucketBits
+	return (histogramSubBuckets + sub) << shift, 1 << shift
+}
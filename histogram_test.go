@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+// TestDurationHistogramQuantiles 测试均匀分布下分位数误差在桶精度范围内
+func TestDurationHistogramQuantiles(t *testing.T) {
+	var h durationHistogram
+	for _, i := range rand.New(rand.NewPCG(1, 2)).Perm(1000) {
+		h.record(time.Duration(i+1) * time.Millisecond)
+	}
+
+	for _, tt := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.5, 500 * time.Millisecond},
+		{0.9, 900 * time.Millisecond},
+		{0.99, 990 * time.Millisecond},
+	} {
+		got := h.quantile(tt.q)
+		if diff := float64(got-tt.want) / float64(tt.want); diff < -0.07 || diff > 0.07 {
+			t.Errorf("quantile(%v) = %v; want %v ±7%%", tt.q, got, tt.want)
+		}
+	}
+	if got := h.quantile(1); got != time.Second {
+		t.Errorf("quantile(1) = %v; want max 1s", got)
+	}
+}
+
+// TestDurationHistogramOutlier 测试单个慢执行不影响中位数
+func TestDurationHistogramOutlier(t *testing.T) {
+	var h durationHistogram
+	for range 99 {
+		h.record(10 * time.Millisecond)
+	}
+	h.record(10 * time.Second)
+
+	if p50 := h.quantile(0.5); p50 != 10*time.Millisecond {
+		t.Errorf("p50 = %v; want 10ms", p50)
+	}
+	if p90 := h.quantile(0.9); p90 != 10*time.Millisecond {
+		t.Errorf("p90 = %v; want 10ms", p90)
+	}
+	if p100 := h.quantile(1); p100 != 10*time.Second {
+		t.Errorf("p100 = %v; want 10s", p100)
+	}
+}
+
+// TestDurationHistogramRecentAverage 测试近期平均只统计最近窗口内的执行
+func TestDurationHistogramRecentAverage(t *testing.T) {
+	var h durationHistogram
+	if h.recentAverage() != 0 || h.quantile(0.5) != 0 {
+		t.Fatal("empty histogram should report zero")
+	}
+
+	for range recentWindowSize {
+		h.record(time.Second)
+	}
+	for range recentWindowSize / 2 {
+		h.record(3 * time.Second)
+	}
+	if got, want := h.recentAverage(), 2*time.Second; got != want {
+		t.Errorf("recentAverage = %v; want %v", got, want)
+	}
+}
+
+// TestHistogramBucketRoundTrip 测试分桶下界与宽度覆盖桶内所有值
+func TestHistogramBucketRoundTrip(t *testing.T) {
+	for _, v := range []uint64{0, 1, 7, 8, 15, 16, 17, 1000, 123456, 1 << 40} {
+		idx := histogramIndex(v)
+		lower, width := histogramBucket(idx)
+		if v < lower || v >= lower+width {
+			t.Errorf("value %d mapped to bucket %d [%d, %d)", v, idx, lower, lower+width)
+		}
+		if width > 1 && float64(width)/float64(lower) > 1.0/histogramSubBuckets+1e-9 {
+			t.Errorf("bucket %d too wide: lower=%d width=%d", idx, lower, width)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// 每个日期文件 <date>.jsonl 旁有一个索引文件 <date>.idx，按行记录开始时间、成功标志与行的字节位置，
+// 定长小端编码。查询与计数只读取索引，分页后仅解码需要返回的行。
+// 索引落后于数据文件（旧版本写入、写入中断）时在读取时按数据文件补齐，损坏时重建。
+const (
+	indexSuffix     = ".idx"
+	indexEntrySize  = 28
+	indexSuccess    = 1 << 0 // 执行成功
+	indexUnreadable = 1 << 1 // 空行或无法解析的行，不计入结果
+)
+
+// indexEntry 数据文件中一行的索引
+type indexEntry struct {
+	sec    int64  // 开始时间（Unix 秒）
+	nsec   uint32 // 开始时间的纳秒部分
+	flags  uint32
+	offset int64  // 行在数据文件中的起始位置
+	length uint32 // 行长度，含换行符
+}
+
+// indexedFields 建立索引所需的记录字段
+type indexedFields struct {
+	StartTime time.Time `json:"startTime"`
+	Success   bool      `json:"success"`
+}
+
+func newIndexEntry(start time.Time, success bool, offset int64, length int) indexEntry {
+	entry := indexEntry{sec: start.Unix(), nsec: uint32(start.Nanosecond()), offset: offset, length: uint32(length)}
+	if success {
+		entry.flags |= indexSuccess
+	}
+	return entry
+}
+
+func (e indexEntry) startTime() time.Time { return time.Unix(e.sec, int64(e.nsec)) }
+
+// end 返回行结束位置，最后一项的 end 等于数据文件大小时索引完整
+func (e indexEntry) end() int64 { return e.offset + int64(e.length) }
+
+// match 判断索引项是否满足过滤条件（任务由目录确定，不含分页）
+func (e indexEntry) match(filter RecordFilter) bool {
+	if e.flags&indexUnreadable != 0 {
+		return false
+	}
+	if filter.StartTime != nil || filter.EndTime != nil {
+		start := e.startTime()
+		if filter.StartTime != nil && start.Before(*filter.StartTime) {
+			return false
+		}
+		if filter.EndTime != nil && start.After(*filter.EndTime) {
+			return false
+		}
+	}
+	success := e.flags&indexSuccess != 0
+	if filter.SuccessOnly && !success {
+		return false
+	}
+	if filter.FailedOnly && success {
+		return false
+	}
+	return true
+}
+
+func (e indexEntry) encode(buf []byte) {
+	binary.LittleEndian.PutUint64(buf[0:], uint64(e.sec))
+	binary.LittleEndian.PutUint32(buf[8:], e.nsec)
+	binary.LittleEndian.PutUint32(buf[12:], e.flags)
+	binary.LittleEndian.PutUint64(buf[16:], uint64(e.offset))
+	binary.LittleEndian.PutUint32(buf[24:], e.length)
+}
+
+func decodeIndexEntry(buf []byte) indexEntry {
+	return indexEntry{
+		sec:    int64(binary.LittleEndian.Uint64(buf[0:])),
+		nsec:   binary.LittleEndian.Uint32(buf[8:]),
+		flags:  binary.LittleEndian.Uint32(buf[12:]),
+		offset: int64(binary.LittleEndian.Uint64(buf[16:])),
+		length: binary.LittleEndian.Uint32(buf[24:]),
+	}
+}
+
+// indexPath 返回数据文件对应的索引文件路径
+func indexPath(dataPath string) string {
+	return strings.TrimSuffix(dataPath, ".jsonl") + indexSuffix
+}
+
+// readIndexFile 读取索引文件，文件不存在或长度不完整时返回 false
+func readIndexFile(path string) ([]indexEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data)%indexEntrySize != 0 {
+		return nil, false
+	}
+	entries := make([]indexEntry, len(data)/indexEntrySize)
+	for i := range entries {
+		entries[i] = decodeIndexEntry(data[i*indexEntrySize:])
+	}
+	return entries, true
+}
+
+// writeIndexFile 以临时文件替换的方式整体写入索引
+func writeIndexFile(path string, entries []indexEntry) error {
+	data := make([]byte, len(entries)*indexEntrySize)
+	for i, entry := range entries {
+		entry.encode(data[i*indexEntrySize:])
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// indexEnd 返回索引覆盖到的数据文件位置
+func indexEnd(entries []indexEntry) int64 {
+	if len(entries) == 0 {
+		return 0
+	}
+	return entries[len(entries)-1].end()
+}
+
+// appendIndex 在 Save 追加数据行后追加对应的索引项。索引与数据文件不一致时不追加，由读取时补齐
+func appendIndex(dataPath string, entry indexEntry) error {
+	file, err := os.OpenFile(indexPath(dataPath), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	if size%indexEntrySize != 0 {
+		return nil
+	}
+	end := int64(0)
+	if size > 0 {
+		last := make([]byte, indexEntrySize)
+		if _, err := file.ReadAt(last, size-indexEntrySize); err != nil {
+			return err
+		}
+		end = decodeIndexEntry(last).end()
+	}
+	if end != entry.offset {
+		return nil
+	}
+
+	buf := make([]byte, indexEntrySize)
+	entry.encode(buf)
+	_, err = file.WriteAt(buf, size)
+	return err
+}
+
+// loadIndex 返回数据文件的完整索引，必要时补齐或重建并写回
+func (fs *FileStorage) loadIndex(dataPath string) ([]indexEntry, error) {
+	info, err := os.Stat(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	idxPath := indexPath(dataPath)
+	if entries, ok := readIndexFile(idxPath); ok && indexEnd(entries) == info.Size() {
+		return entries, nil
+	}
+
+	fs.indexMu.Lock()
+	defer fs.indexMu.Unlock()
+
+	// 其他查询可能已完成修复
+	entries, ok := readIndexFile(idxPath)
+	end := indexEnd(entries)
+	if ok && end == info.Size() {
+		return entries, nil
+	}
+	if !ok || end > info.Size() {
+		entries, end = nil, 0
+	}
+
+	added, err := fs.scanIndexEntries(dataPath, end, len(entries))
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, added...)
+	if err := writeIndexFile(idxPath, entries); err != nil {
+		safeWarn(fs.logger, "写入历史记录索引失败",
+			"file", idxPath,
+			"error", err.Error())
+	}
+	return entries, nil
+}
+
+// scanIndexEntries 从 offset 开始逐行扫描数据文件生成索引项，skipped 为 offset 之前的行数（用于日志行号）
+func (fs *FileStorage) scanIndexEntries(dataPath string, offset int64, skipped int) ([]indexEntry, error) {
+	file, err := os.Open(dataPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var entries []indexEntry
+	reader := bufio.NewReader(file)
+	for lineNum := skipped + 1; ; lineNum++ {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			entry := indexEntry{offset: offset, length: uint32(len(line)), flags: indexUnreadable}
+			if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+				var fields indexedFields
+				if jsonErr := json.Unmarshal(trimmed, &fields); jsonErr != nil {
+					safeWarn(fs.logger, "解析历史记录 JSON 失败，跳过该行",
+						"file", dataPath,
+						"line", lineNum,
+						"error", jsonErr.Error())
+				} else {
+					entry = newIndexEntry(fields.StartTime, fields.Success, offset, len(line))
+				}
+			}
+			entries = append(entries, entry)
+			offset += int64(len(line))
+		}
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// latestStartOn 返回日期文件中记录开始时间的上界。文件按记录自身时区的日期分片，
+// 时区偏移最大为 -12 小时，因此上界为次日 12:00 UTC
+func latestStartOn(date string) time.Time {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Unix(1<<62, 0)
+	}
+	return day.Add(36 * time.Hour)
+}
@@ -0,0 +1,237 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// recordIDs 返回记录 ID 列表，用于比较查询结果
+func recordIDs(records []*ExecutionRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+// TestFileStorageIndexedQueryMatchesFullScan 测试基于索引的分页查询与计数与逐条过滤的结果一致
+func TestFileStorageIndexedQueryMatchesFullScan(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+	reference := NewMemoryStorage(1000, 1000)
+
+	// 多个任务跨 5 天，写入顺序与开始时间不一致
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 150 {
+		start := base.Add(time.Duration((i*37)%150) * 47 * time.Minute)
+		record := &ExecutionRecord{
+			ID:        fmt.Sprintf("r%03d", i),
+			TaskID:    fmt.Sprintf("task-%d", i%3),
+			StartTime: start,
+			EndTime:   start.Add(time.Second),
+			Success:   i%4 != 0,
+		}
+		if err := storage.Save(record); err != nil {
+			t.Fatalf("保存记录失败: %v", err)
+		}
+		if err := reference.Save(record); err != nil {
+			t.Fatalf("保存记录失败: %v", err)
+		}
+	}
+
+	from, to := base.Add(30*time.Hour), base.Add(80*time.Hour)
+	filters := []RecordFilter{
+		{},
+		{Limit: 10},
+		{Offset: 25, Limit: 10},
+		{Offset: 140, Limit: 20},
+		{Offset: 200},
+		{TaskID: "task-1", Limit: 7},
+		{TaskID: "task-2", FailedOnly: true},
+		{SuccessOnly: true, Offset: 3, Limit: 30},
+		{StartTime: &from, EndTime: &to, Limit: 15},
+		{TaskID: "task-0", StartTime: &from, FailedOnly: true},
+		{EndTime: &from, Offset: 5, Limit: 5},
+	}
+	for i, filter := range filters {
+		got, err := storage.Query(filter)
+		if err != nil {
+			t.Fatalf("过滤器 %d 查询失败: %v", i, err)
+		}
+		want, _ := reference.Query(filter)
+		if fmt.Sprint(recordIDs(got)) != fmt.Sprint(recordIDs(want)) {
+			t.Fatalf("过滤器 %d 查询结果 = %v, 期望 %v", i, recordIDs(got), recordIDs(want))
+		}
+
+		count, err := storage.Count(filter)
+		wantCount, _ := reference.Count(filter)
+		if err != nil || count != wantCount {
+			t.Fatalf("过滤器 %d 计数 = %d (%v), 期望 %d", i, count, err, wantCount)
+		}
+	}
+}
+
+// TestFileStorageIndexRepair 测试无索引、索引落后与索引损坏时按数据文件补齐
+func TestFileStorageIndexRepair(t *testing.T) {
+	tmpDir := t.TempDir()
+	storage, err := NewFileStorage(tmpDir)
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+
+	taskDir := filepath.Join(tmpDir, "task")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatalf("创建任务目录失败: %v", err)
+	}
+	dataFile := filepath.Join(taskDir, "2025-01-01.jsonl")
+	appendLine := func(id string, minute int) {
+		start := time.Date(2025, 1, 1, 0, minute, 0, 0, time.UTC)
+		data, _ := json.Marshal(&ExecutionRecord{ID: id, TaskID: "task", StartTime: start, Success: true})
+		file, err := os.OpenFile(dataFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatalf("打开数据文件失败: %v", err)
+		}
+		defer file.Close()
+		if _, err := file.Write(append(data, '\n')); err != nil {
+			t.Fatalf("写入数据文件失败: %v", err)
+		}
+	}
+	query := func() []string {
+		t.Helper()
+		records, err := storage.Query(RecordFilter{TaskID: "task"})
+		if err != nil {
+			t.Fatalf("查询失败: %v", err)
+		}
+		return recordIDs(records)
+	}
+
+	// 旧版本写入的数据文件没有索引
+	appendLine("a", 1)
+	appendLine("b", 2)
+	if got := query(); fmt.Sprint(got) != "[b a]" {
+		t.Fatalf("无索引时查询结果 = %v", got)
+	}
+	if _, err := os.Stat(indexPath(dataFile)); err != nil {
+		t.Fatalf("查询后应生成索引: %v", err)
+	}
+
+	// 其他写入方追加后索引落后，Save 不在不一致的索引上追加
+	appendLine("c", 3)
+	start := time.Date(2025, 1, 1, 0, 4, 0, 0, time.UTC)
+	if err := storage.Save(&ExecutionRecord{ID: "d", TaskID: "task", StartTime: start}); err != nil {
+		t.Fatalf("保存记录失败: %v", err)
+	}
+	if got := query(); fmt.Sprint(got) != "[d c b a]" {
+		t.Fatalf("索引落后时查询结果 = %v", got)
+	}
+
+	// 索引损坏时重建
+	if err := os.WriteFile(indexPath(dataFile), []byte("broken"), 0o644); err != nil {
+		t.Fatalf("写入索引失败: %v", err)
+	}
+	if count, _ := storage.Count(RecordFilter{TaskID: "task", FailedOnly: true}); count != 1 {
+		t.Fatalf("索引损坏时计数 = %d, 期望 1", count)
+	}
+	if info, _ := os.Stat(indexPath(dataFile)); info.Size() != 4*indexEntrySize {
+		t.Fatalf("索引未重建, 大小 %d", info.Size())
+	}
+
+	// 删除数据文件时一并删除索引
+	if deleted, err := storage.Delete(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)); err != nil || deleted != 4 {
+		t.Fatalf("删除数量 = %d (%v), 期望 4", deleted, err)
+	}
+	if _, err := os.Stat(taskDir); !os.IsNotExist(err) {
+		t.Fatalf("删除后任务目录应被移除: %v", err)
+	}
+}
+
+// writeHistoryFixture 直接写入 days 天、每分钟一条的数据文件（不含索引），返回最后一条记录的开始时间
+func writeHistoryFixture(b *testing.B, baseDir, taskID string, days int) time.Time {
+	b.Helper()
+	taskDir := filepath.Join(baseDir, taskID)
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		b.Fatalf("创建任务目录失败: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var last time.Time
+	for day := range days {
+		date := start.AddDate(0, 0, day)
+		file, err := os.Create(filepath.Join(taskDir, date.Format("2006-01-02")+".jsonl"))
+		if err != nil {
+			b.Fatalf("创建数据文件失败: %v", err)
+		}
+		writer := bufio.NewWriter(file)
+		for minute := range 24 * 60 {
+			last = date.Add(time.Duration(minute) * time.Minute)
+			data, _ := json.Marshal(&ExecutionRecord{
+				ID:        fmt.Sprintf("%s_%d", taskID, last.Unix()),
+				TaskID:    taskID,
+				StartTime: last,
+				EndTime:   last.Add(time.Second),
+				Duration:  time.Second,
+				Success:   minute%10 != 0,
+			})
+			_, _ = writer.Write(append(data, '\n'))
+		}
+		if err := writer.Flush(); err != nil {
+			b.Fatalf("写入数据文件失败: %v", err)
+		}
+		_ = file.Close()
+	}
+	return last
+}
+
+// BenchmarkFileStorageLargeHistory 在 90 天、每分钟一条（约 13 万条）的数据上测试分页查询与计数
+func BenchmarkFileStorageLargeHistory(b *testing.B) {
+	storage, err := NewFileStorage(b.TempDir())
+	if err != nil {
+		b.Fatalf("创建存储失败: %v", err)
+	}
+	last := writeHistoryFixture(b, storage.baseDir, "minutely", 90)
+	weekAgo := last.Add(-7 * 24 * time.Hour)
+
+	// 首次访问为旧数据建立索引
+	if _, err := storage.Count(RecordFilter{}); err != nil {
+		b.Fatalf("建立索引失败: %v", err)
+	}
+
+	benchmarks := []struct {
+		name string
+		run  func() error
+	}{
+		{"QueryNewestPage", func() error {
+			_, err := storage.Query(RecordFilter{TaskID: "minutely", Limit: 20})
+			return err
+		}},
+		{"QueryDeepPage", func() error {
+			_, err := storage.Query(RecordFilter{TaskID: "minutely", Offset: 10000, Limit: 20})
+			return err
+		}},
+		{"QueryFailedLastWeek", func() error {
+			_, err := storage.Query(RecordFilter{StartTime: &weekAgo, FailedOnly: true, Limit: 50})
+			return err
+		}},
+		{"Count", func() error {
+			_, err := storage.Count(RecordFilter{TaskID: "minutely"})
+			return err
+		}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := bm.run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
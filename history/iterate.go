@@ -0,0 +1,322 @@
+package history
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+var (
+	// ErrInvalidCursor 游标无法解析
+	ErrInvalidCursor = errors.New("invalid history cursor")
+	// ErrIterationUnsupported 存储或记录器未实现 RecordIterator
+	ErrIterationUnsupported = errors.New("history storage does not support iteration")
+)
+
+// cursorPosition 游标对应的记录位置。记录按开始时间倒序，开始时间相同时依次按任务 ID 升序、
+// 日期文件倒序、位置升序排列，构成全序，新写入的记录不会改变已有记录之间的先后关系
+type cursorPosition struct {
+	Sec  int64  `json:"s"`
+	Nsec uint32 `json:"n"`
+	Task string `json:"t"`
+	Date string `json:"d,omitempty"` // 日期文件，MemoryStorage 为空
+	Pos  int64  `json:"p"`           // 行在数据文件中的位置，MemoryStorage 为写入序号
+}
+
+// encode 编码为不透明的游标字符串
+func (p cursorPosition) encode() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (p cursorPosition) startTime() time.Time { return time.Unix(p.Sec, int64(p.Nsec)) }
+
+// decodeCursor 解析游标字符串，空字符串返回 false
+func decodeCursor(cursor string) (cursorPosition, bool, error) {
+	if cursor == "" {
+		return cursorPosition{}, false, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return cursorPosition{}, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	var pos cursorPosition
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return cursorPosition{}, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return pos, true, nil
+}
+
+// compareCursor 比较两个位置的遍历顺序，a 先于 b 时返回负数
+func compareCursor(a, b cursorPosition) int {
+	if c := cmp.Compare(b.Sec, a.Sec); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Nsec, a.Nsec); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Task, b.Task); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Date, a.Date); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Pos, b.Pos)
+}
+
+// iteratePage 在遍历中应用 Offset / Limit
+type iteratePage struct {
+	skip, remaining int
+}
+
+func newIteratePage(filter RecordFilter) iteratePage {
+	remaining := -1
+	if filter.Limit > 0 {
+		remaining = filter.Limit
+	}
+	return iteratePage{skip: filter.Offset, remaining: remaining}
+}
+
+// take 判断当前记录是否在分页范围内
+func (p *iteratePage) take() bool {
+	if p.skip > 0 {
+		p.skip--
+		return false
+	}
+	return true
+}
+
+// emitted 记录已返回一条记录，达到 Limit 时返回 true
+func (p *iteratePage) emitted() bool {
+	if p.remaining > 0 {
+		p.remaining--
+	}
+	return p.remaining == 0
+}
+
+// earliestStartOn 返回日期文件中记录开始时间的下界。时区偏移最大为 +14 小时，因此下界为前一日 10:00 UTC
+func earliestStartOn(date string) time.Time {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return time.Time{}
+	}
+	return day.Add(-14 * time.Hour)
+}
+
+// iterateMatch 遍历中待返回的索引项
+type iterateMatch struct {
+	path string
+	pos  cursorPosition
+	line indexEntry
+}
+
+// iterateFiles 遍历期间打开的数据文件，文件中待返回的索引项全部处理后关闭
+type iterateFiles struct {
+	fs      *FileStorage
+	files   map[string]*os.File
+	pending map[string]int
+}
+
+func (f *iterateFiles) add(path string) { f.pending[path]++ }
+
+// read 按索引位置读取并解码记录，读取或解析失败时记录日志并返回 nil
+func (f *iterateFiles) read(match iterateMatch) *ExecutionRecord {
+	defer f.done(match.path)
+
+	file, ok := f.files[match.path]
+	if !ok {
+		var err error
+		if file, err = os.Open(match.path); err != nil {
+			safeWarn(f.fs.logger, "读取历史记录文件失败",
+				"file", match.path,
+				"error", err.Error())
+		}
+		f.files[match.path] = file
+	}
+	if file == nil {
+		return nil
+	}
+
+	line := make([]byte, match.line.length)
+	if _, err := file.ReadAt(line, match.line.offset); err != nil && !errors.Is(err, io.EOF) {
+		safeWarn(f.fs.logger, "读取历史记录文件失败",
+			"file", match.path,
+			"error", err.Error())
+		return nil
+	}
+	var record ExecutionRecord
+	if err := json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+		safeWarn(f.fs.logger, "解析历史记录 JSON 失败，跳过该行",
+			"file", match.path,
+			"error", err.Error())
+		return nil
+	}
+	return &record
+}
+
+// done 标记一个索引项处理完毕
+func (f *iterateFiles) done(path string) {
+	f.pending[path]--
+	if f.pending[path] > 0 {
+		return
+	}
+	delete(f.pending, path)
+	if file := f.files[path]; file != nil {
+		_ = file.Close()
+	}
+	delete(f.files, path)
+}
+
+func (f *iterateFiles) close() {
+	for _, file := range f.files {
+		if file != nil {
+			_ = file.Close()
+		}
+	}
+}
+
+// Iterate 按开始时间倒序逐条遍历记录，实现 RecordIterator。
+// 日期文件从新到旧按需读取索引，只有确定不会再出现更新的记录时才解码并交给 fn，
+// 内存占用与单日索引大小成正比；持有存储读锁的时间仅限于读取每一天的索引
+func (fs *FileStorage) Iterate(ctx context.Context, filter RecordFilter, fn func(record *ExecutionRecord, cursor string) error) error {
+	after, resume, err := decodeCursor(filter.Cursor)
+	if err != nil {
+		return err
+	}
+
+	fs.mu.RLock()
+	days, err := fs.getDays(filter, true)
+	fs.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	files := &iterateFiles{fs: fs, files: make(map[string]*os.File), pending: make(map[string]int)}
+	defer files.close()
+	page := newIteratePage(filter)
+
+	var pending []iterateMatch
+	for i := 0; i < len(days) || len(pending) > 0; {
+		// 读取下一天的索引，直到待返回的首项一定早于之后所有日期文件中的记录
+		if i < len(days) && (len(pending) == 0 || pending[0].pos.startTime().Before(latestStartOn(days[i].date))) {
+			day := days[i]
+			i++
+			if resume && earliestStartOn(day.date).After(after.startTime()) {
+				continue // 整天都在游标之前
+			}
+			pending = append(pending, fs.loadDayMatches(day, filter, after, resume, files)...)
+			slices.SortFunc(pending, func(a, b iterateMatch) int {
+				return compareCursor(a.pos, b.pos)
+			})
+			continue
+		}
+
+		match := pending[0]
+		pending = pending[1:]
+		if !page.take() {
+			files.done(match.path)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		record := files.read(match)
+		if record == nil {
+			continue
+		}
+		if err := fn(record, match.pos.encode()); err != nil {
+			return err
+		}
+		if page.emitted() {
+			return nil
+		}
+	}
+	return nil
+}
+
+// loadDayMatches 读取一天内各数据文件的索引，返回满足过滤条件且位于游标之后的项
+func (fs *FileStorage) loadDayMatches(day dayFiles, filter RecordFilter, after cursorPosition, resume bool, files *iterateFiles) []iterateMatch {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var matches []iterateMatch
+	for _, dateFile := range day.files {
+		entries, err := fs.loadIndex(dateFile)
+		if err != nil {
+			safeWarn(fs.logger, "读取历史记录文件失败",
+				"file", dateFile,
+				"error", err.Error())
+			continue
+		}
+		task := filepath.Base(filepath.Dir(dateFile))
+		for _, entry := range entries {
+			if !entry.match(filter) {
+				continue
+			}
+			pos := cursorPosition{Sec: entry.sec, Nsec: entry.nsec, Task: task, Date: day.date, Pos: entry.offset}
+			if resume && compareCursor(pos, after) <= 0 {
+				continue
+			}
+			matches = append(matches, iterateMatch{path: dateFile, pos: pos, line: entry})
+			files.add(dateFile)
+		}
+	}
+	return matches
+}
+
+// Iterate 按开始时间倒序逐条遍历记录副本，实现 RecordIterator。
+// 遍历前在读锁下取得满足条件的记录快照，回调期间不持有锁
+func (ms *MemoryStorage) Iterate(ctx context.Context, filter RecordFilter, fn func(record *ExecutionRecord, cursor string) error) error {
+	after, resume, err := decodeCursor(filter.Cursor)
+	if err != nil {
+		return err
+	}
+
+	type memoryMatch struct {
+		pos    cursorPosition
+		record *ExecutionRecord
+	}
+	var matches []memoryMatch
+	ms.mu.RLock()
+	err = ms.scanEntries(filter, func(taskID string, entry memoryEntry) {
+		start := entry.record.StartTime
+		pos := cursorPosition{Sec: start.Unix(), Nsec: uint32(start.Nanosecond()), Task: taskID, Pos: int64(entry.seq)}
+		if !resume || compareCursor(pos, after) > 0 {
+			matches = append(matches, memoryMatch{pos: pos, record: entry.record})
+		}
+	})
+	ms.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(matches, func(a, b memoryMatch) int {
+		return compareCursor(a.pos, b.pos)
+	})
+	page := newIteratePage(filter)
+	for _, match := range matches {
+		if !page.take() {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// 已保存的记录不会被修改，快照中的指针在释放锁后仍可安全复制
+		if err := fn(cloneRecord(match.record), match.pos.encode()); err != nil {
+			return err
+		}
+		if page.emitted() {
+			return nil
+		}
+	}
+	return nil
+}
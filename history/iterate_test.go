@@ -0,0 +1,226 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// iteratePages 以 pageSize 为一页、通过游标依次取完全部记录，返回记录 ID 与页数
+func iteratePages(t *testing.T, iterator RecordIterator, filter RecordFilter, pageSize int) ([]string, int) {
+	t.Helper()
+	var ids []string
+	pages := 0
+	filter.Limit = pageSize
+	for {
+		n := 0
+		err := iterator.Iterate(context.Background(), filter, func(record *ExecutionRecord, cursor string) error {
+			ids = append(ids, record.ID)
+			filter.Cursor = cursor
+			n++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("遍历失败: %v", err)
+		}
+		if n == 0 {
+			return ids, pages
+		}
+		pages++
+	}
+}
+
+// TestIterateCursorPagination 测试按游标分页遍历的结果与 Query 一致，FileStorage 与 MemoryStorage 行为相同
+func TestIterateCursorPagination(t *testing.T) {
+	fileStorage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+	storages := map[string]interface {
+		Storage
+		RecordIterator
+	}{
+		"file":   fileStorage,
+		"memory": NewMemoryStorage(1000, 1000),
+	}
+
+	// 3 个任务跨 4 天，含开始时间相同的记录
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 90 {
+		start := base.Add(time.Duration((i*7)%45) * 2 * time.Hour)
+		record := &ExecutionRecord{
+			ID:        fmt.Sprintf("r%02d", i),
+			TaskID:    fmt.Sprintf("task-%d", i%3),
+			StartTime: start,
+			Success:   i%5 != 0,
+		}
+		for _, storage := range storages {
+			if err := storage.Save(record); err != nil {
+				t.Fatalf("保存记录失败: %v", err)
+			}
+		}
+	}
+
+	from := base.Add(20 * time.Hour)
+	filters := []RecordFilter{
+		{},
+		{TaskID: "task-1"},
+		{FailedOnly: true},
+		{StartTime: &from, SuccessOnly: true},
+	}
+	for name, storage := range storages {
+		for i, filter := range filters {
+			all, err := storage.Query(filter)
+			if err != nil {
+				t.Fatalf("%s 过滤器 %d 查询失败: %v", name, i, err)
+			}
+			want := recordIDs(all)
+			got, pages := iteratePages(t, storage, filter, 7)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("%s 过滤器 %d 游标分页结果 = %v, 期望 %v", name, i, got, want)
+			}
+			if wantPages := (len(want) + 6) / 7; pages != wantPages {
+				t.Fatalf("%s 过滤器 %d 页数 = %d, 期望 %d", name, i, pages, wantPages)
+			}
+		}
+
+		// 游标之后应用 Offset
+		var cursor string
+		_ = storage.Iterate(context.Background(), RecordFilter{Limit: 10}, func(_ *ExecutionRecord, c string) error {
+			cursor = c
+			return nil
+		})
+		page, _ := storage.Query(RecordFilter{Offset: 13, Limit: 5})
+		var got []string
+		_ = storage.Iterate(context.Background(), RecordFilter{Cursor: cursor, Offset: 3, Limit: 5}, func(record *ExecutionRecord, _ string) error {
+			got = append(got, record.ID)
+			return nil
+		})
+		if fmt.Sprint(got) != fmt.Sprint(recordIDs(page)) {
+			t.Fatalf("%s 游标加偏移结果 = %v, 期望 %v", name, got, recordIDs(page))
+		}
+	}
+}
+
+// TestIterateCursorStable 测试游标在新记录写入后仍指向原位置：更新的记录不重复返回，更早的记录按顺序出现
+func TestIterateCursorStable(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	save := func(id string, start time.Time) {
+		t.Helper()
+		if err := storage.Save(&ExecutionRecord{ID: id, TaskID: "task", StartTime: start}); err != nil {
+			t.Fatalf("保存记录失败: %v", err)
+		}
+	}
+	for i := range 6 {
+		save(fmt.Sprintf("r%d", i), base.Add(time.Duration(i)*time.Hour))
+	}
+
+	var cursor string
+	_ = storage.Iterate(context.Background(), RecordFilter{Limit: 2}, func(_ *ExecutionRecord, c string) error {
+		cursor = c
+		return nil
+	})
+
+	save("newer", base.Add(24*time.Hour))
+	save("older", base.Add(150*time.Minute))
+	save("tie", base.Add(4*time.Hour)) // 与游标位置开始时间相同，写入更晚
+	var got []string
+	err = storage.Iterate(context.Background(), RecordFilter{Cursor: cursor}, func(record *ExecutionRecord, _ string) error {
+		got = append(got, record.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("遍历失败: %v", err)
+	}
+	if want := "[tie r3 older r2 r1 r0]"; fmt.Sprint(got) != want {
+		t.Fatalf("恢复后结果 = %v, 期望 %s", got, want)
+	}
+}
+
+// TestIterateStop 测试回调返回错误、上下文取消与非法游标时停止遍历
+func TestIterateStop(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		if err := storage.Save(&ExecutionRecord{TaskID: "task", StartTime: start.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatalf("保存记录失败: %v", err)
+		}
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err = storage.Iterate(context.Background(), RecordFilter{}, func(*ExecutionRecord, string) error {
+		calls++
+		if calls == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) || calls != 2 {
+		t.Fatalf("回调返回错误后应停止: calls=%d err=%v", calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = storage.Iterate(ctx, RecordFilter{}, func(*ExecutionRecord, string) error {
+		calls++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatalf("上下文取消后应停止: calls=%d err=%v", calls, err)
+	}
+
+	for _, cursor := range []string{"!!", "bm90LWpzb24"} {
+		err = storage.Iterate(context.Background(), RecordFilter{Cursor: cursor}, func(*ExecutionRecord, string) error { return nil })
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("游标 %q 应返回 ErrInvalidCursor, 实际 %v", cursor, err)
+		}
+	}
+}
+
+// plainStorage 未实现 RecordIterator 的存储
+type plainStorage struct{ Storage }
+
+// TestHistoryRecorderIterate 测试记录器委托存储遍历，存储不支持时返回 ErrIterationUnsupported
+func TestHistoryRecorderIterate(t *testing.T) {
+	storage := NewMemoryStorage(0, 0)
+	recorder, err := NewHistoryRecorder(storage)
+	if err != nil {
+		t.Fatalf("创建记录器失败: %v", err)
+	}
+	recorder.Record("task", time.Now(), time.Now(), true, 0, nil)
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("关闭记录器失败: %v", err)
+	}
+
+	calls := 0
+	err = recorder.Iterate(context.Background(), RecordFilter{TaskID: "task"}, func(record *ExecutionRecord, cursor string) error {
+		calls++
+		if cursor == "" {
+			t.Fatal("游标不应为空")
+		}
+		return nil
+	})
+	if err != nil || calls != 1 {
+		t.Fatalf("遍历结果 calls=%d err=%v", calls, err)
+	}
+
+	unsupported, err := NewHistoryRecorder(plainStorage{storage})
+	if err != nil {
+		t.Fatalf("创建记录器失败: %v", err)
+	}
+	defer func() { _ = unsupported.Close() }()
+	if err := unsupported.Iterate(context.Background(), RecordFilter{}, func(*ExecutionRecord, string) error { return nil }); !errors.Is(err, ErrIterationUnsupported) {
+		t.Fatalf("期望 ErrIterationUnsupported, 实际 %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+package history
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMemoryTaskCapacity MemoryStorage 每个任务默认保留的记录数
+	DefaultMemoryTaskCapacity = 1000
+	// DefaultMemoryTotalCapacity MemoryStorage 默认保留的记录总数
+	DefaultMemoryTotalCapacity = 10000
+)
+
+// memoryEntry 环形缓冲区中的一条记录，seq 为全局写入序号
+type memoryEntry struct {
+	seq    uint64
+	record *ExecutionRecord
+}
+
+// memoryRing 单个任务的环形缓冲区，按写入顺序保存记录，容量按需增长至上限
+type memoryRing struct {
+	buf  []memoryEntry
+	head int
+	size int
+}
+
+func (r *memoryRing) push(entry memoryEntry, limit int) {
+	if r.size == len(r.buf) {
+		grown := make([]memoryEntry, min(max(2*len(r.buf), 8), limit))
+		for i := range r.size {
+			grown[i] = r.at(i)
+		}
+		r.buf, r.head = grown, 0
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = entry
+	r.size++
+}
+
+// pop 移除最早的记录
+func (r *memoryRing) pop() {
+	r.buf[r.head] = memoryEntry{}
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+}
+
+// at 返回第 i 早的记录
+func (r *memoryRing) at(i int) memoryEntry {
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// memoryRef 全局写入顺序中的一项，对应记录可能已被任务容量淘汰
+type memoryRef struct {
+	seq    uint64
+	taskID string
+}
+
+// MemoryStorage 基于内存环形缓冲区的历史记录存储，适合测试与无需落盘的小型服务。
+// 每个任务最多保留 taskCapacity 条记录，全部任务合计最多 totalCapacity 条，超出时淘汰最早写入的记录。
+// 写入为 O(1)（均摊），进程退出后记录丢失。
+type MemoryStorage struct {
+	taskCapacity  int
+	totalCapacity int
+
+	mu    sync.RWMutex
+	tasks map[string]*memoryRing
+	order []memoryRef // 全局写入顺序，可能含已被任务容量淘汰的项
+	front int         // order 中第一个未出队的位置
+	size  int         // 当前保存的记录总数
+	seq   uint64
+}
+
+// NewMemoryStorage 创建内存存储实例。taskCapacity 为每个任务保留的记录数，totalCapacity 为记录总数上限，
+// <= 0 时分别使用 DefaultMemoryTaskCapacity 与 DefaultMemoryTotalCapacity
+func NewMemoryStorage(taskCapacity, totalCapacity int) *MemoryStorage {
+	if taskCapacity <= 0 {
+		taskCapacity = DefaultMemoryTaskCapacity
+	}
+	if totalCapacity <= 0 {
+		totalCapacity = DefaultMemoryTotalCapacity
+	}
+	return &MemoryStorage{
+		taskCapacity:  taskCapacity,
+		totalCapacity: totalCapacity,
+		tasks:         make(map[string]*memoryRing),
+	}
+}
+
+// Save 保存一条执行记录，超出容量时淘汰最早的记录
+func (ms *MemoryStorage) Save(record *ExecutionRecord) error {
+	if record == nil {
+		return fmt.Errorf("record cannot be nil")
+	}
+	taskID, err := normalizeStorageTaskID(record.TaskID)
+	if err != nil {
+		return err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ring := ms.tasks[taskID]; ring != nil && ring.size == ms.taskCapacity {
+		ring.pop()
+		ms.size--
+	} else if ms.size == ms.totalCapacity {
+		ms.evictOldest()
+	}
+	// 全局淘汰可能清空并移除同一任务的缓冲区，淘汰后再取
+	ring := ms.tasks[taskID]
+	if ring == nil {
+		ring = &memoryRing{}
+		ms.tasks[taskID] = ring
+	}
+
+	ms.seq++
+	ring.push(memoryEntry{seq: ms.seq, record: cloneRecord(record)}, ms.taskCapacity)
+	ms.order = append(ms.order, memoryRef{seq: ms.seq, taskID: taskID})
+	ms.size++
+	ms.compactOrder()
+	return nil
+}
+
+// evictOldest 淘汰全局最早写入且仍在保存中的记录
+func (ms *MemoryStorage) evictOldest() {
+	for ms.front < len(ms.order) {
+		ref := ms.order[ms.front]
+		ms.order[ms.front] = memoryRef{}
+		ms.front++
+		if !ms.live(ref) {
+			continue
+		}
+		ring := ms.tasks[ref.taskID]
+		ring.pop()
+		ms.size--
+		if ring.size == 0 {
+			delete(ms.tasks, ref.taskID)
+		}
+		return
+	}
+}
+
+// live 判断 order 中的项是否仍在保存中：同一任务的记录按写入顺序淘汰，序号不早于缓冲区首项即仍在保存
+func (ms *MemoryStorage) live(ref memoryRef) bool {
+	ring := ms.tasks[ref.taskID]
+	return ring != nil && ring.size > 0 && ref.seq >= ring.at(0).seq
+}
+
+// compactOrder 在已出队或已淘汰的项占多数时重建 order，保证写入均摊 O(1)、内存与记录数成正比
+func (ms *MemoryStorage) compactOrder() {
+	if len(ms.order) < 64 || len(ms.order) < 2*ms.size {
+		return
+	}
+	live := make([]memoryRef, 0, 2*ms.size)
+	for _, ref := range ms.order[ms.front:] {
+		if ms.live(ref) {
+			live = append(live, ref)
+		}
+	}
+	ms.order, ms.front = live, 0
+}
+
+// Query 根据过滤器查询记录，按开始时间倒序返回记录副本，顺序与 Iterate 一致
+func (ms *MemoryStorage) Query(filter RecordFilter) ([]*ExecutionRecord, error) {
+	filter.Cursor = ""
+	records := []*ExecutionRecord{}
+	err := ms.Iterate(context.Background(), filter, func(record *ExecutionRecord, _ string) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Count 统计符合条件的记录数量
+func (ms *MemoryStorage) Count(filter RecordFilter) (int, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	total := 0
+	err := ms.scan(filter, func(*ExecutionRecord) {
+		total++
+	})
+	return total, err
+}
+
+// scan 遍历满足过滤条件的记录，调用方需持有读锁
+func (ms *MemoryStorage) scan(filter RecordFilter, fn func(*ExecutionRecord)) error {
+	return ms.scanEntries(filter, func(_ string, entry memoryEntry) {
+		fn(entry.record)
+	})
+}
+
+// scanEntries 遍历满足过滤条件的缓冲区项及其任务 ID，调用方需持有读锁
+func (ms *MemoryStorage) scanEntries(filter RecordFilter, fn func(taskID string, entry memoryEntry)) error {
+	visit := func(taskID string, ring *memoryRing) {
+		for i := range ring.size {
+			if entry := ring.at(i); matchFilter(entry.record, filter) {
+				fn(taskID, entry)
+			}
+		}
+	}
+
+	if filter.TaskID == "" {
+		for taskID, ring := range ms.tasks {
+			visit(taskID, ring)
+		}
+		return nil
+	}
+	taskID, err := normalizeStorageTaskID(filter.TaskID)
+	if err != nil {
+		return err
+	}
+	if ring := ms.tasks[taskID]; ring != nil {
+		visit(taskID, ring)
+	}
+	return nil
+}
+
+// Delete 删除开始时间早于 before 的记录
+func (ms *MemoryStorage) Delete(before time.Time) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	deleted := 0
+	for taskID, ring := range ms.tasks {
+		kept := &memoryRing{}
+		for i := range ring.size {
+			if entry := ring.at(i); entry.record.StartTime.Before(before) {
+				deleted++
+			} else {
+				kept.push(entry, ms.taskCapacity)
+			}
+		}
+		if kept.size == 0 {
+			delete(ms.tasks, taskID)
+		} else if kept.size < ring.size {
+			ms.tasks[taskID] = kept
+		}
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	// 被删除的记录可能位于缓冲区中间，其在 order 中的项仍会被 live 判定为保存中，因此按剩余记录重建写入顺序
+	ms.size -= deleted
+	ms.order = ms.order[:0:0]
+	ms.front = 0
+	for taskID, ring := range ms.tasks {
+		for i := range ring.size {
+			ms.order = append(ms.order, memoryRef{seq: ring.at(i).seq, taskID: taskID})
+		}
+	}
+	slices.SortFunc(ms.order, func(a, b memoryRef) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	return deleted, nil
+}
+
+// Close 关闭存储。内存存储没有需要释放的资源，关闭后记录仍可查询
+func (ms *MemoryStorage) Close() error { return nil }
+
+// cloneRecord 复制记录，避免调用方修改已保存的数据
+func cloneRecord(record *ExecutionRecord) *ExecutionRecord {
+	cloned := *record
+	if record.Resources != nil {
+		resources := *record.Resources
+		cloned.Resources = &resources
+	}
+	return &cloned
+}
@@ -0,0 +1,197 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// saveMemoryRecord 向内存存储写入一条记录
+func saveMemoryRecord(t testing.TB, storage *MemoryStorage, taskID string, start time.Time, success bool) {
+	t.Helper()
+	record := &ExecutionRecord{
+		ID:        fmt.Sprintf("%s_%d", taskID, start.UnixNano()),
+		TaskID:    taskID,
+		StartTime: start,
+		EndTime:   start.Add(time.Second),
+		Duration:  time.Second,
+		Success:   success,
+	}
+	if err := storage.Save(record); err != nil {
+		t.Fatalf("保存记录失败: %v", err)
+	}
+}
+
+// TestMemoryStorageQueryFilter 测试查询支持 RecordFilter 的全部字段并按开始时间倒序返回
+func TestMemoryStorageQueryFilter(t *testing.T) {
+	storage := NewMemoryStorage(0, 0)
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 6 {
+		saveMemoryRecord(t, storage, "a", base.Add(time.Duration(i)*time.Hour), i%2 == 0)
+	}
+	saveMemoryRecord(t, storage, "b", base.Add(90*time.Minute), false)
+
+	start, end := base.Add(time.Hour), base.Add(4*time.Hour)
+	tests := []struct {
+		name   string
+		filter RecordFilter
+		want   []string
+	}{
+		{"全部任务", RecordFilter{Limit: 3}, []string{"a_5", "a_4", "a_3"}},
+		{"指定任务", RecordFilter{TaskID: "b"}, []string{"b_1.5"}},
+		{"时间范围", RecordFilter{TaskID: "a", StartTime: &start, EndTime: &end}, []string{"a_4", "a_3", "a_2", "a_1"}},
+		{"仅成功", RecordFilter{TaskID: "a", SuccessOnly: true}, []string{"a_4", "a_2", "a_0"}},
+		{"仅失败", RecordFilter{FailedOnly: true, StartTime: &start, EndTime: &end}, []string{"a_3", "b_1.5", "a_1"}},
+		{"分页", RecordFilter{TaskID: "a", Offset: 2, Limit: 2}, []string{"a_3", "a_2"}},
+		{"偏移超出", RecordFilter{Offset: 10}, nil},
+		{"未知任务", RecordFilter{TaskID: "missing"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := storage.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("查询失败: %v", err)
+			}
+			var got []string
+			for _, record := range records {
+				got = append(got, fmt.Sprintf("%s_%g", record.TaskID, record.StartTime.Sub(base).Hours()))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("查询结果 = %v, 期望 %v", got, tt.want)
+			}
+
+			countFilter := tt.filter
+			countFilter.Offset, countFilter.Limit = 0, 0
+			all, _ := storage.Query(countFilter)
+			if count, err := storage.Count(tt.filter); err != nil || count != len(all) {
+				t.Fatalf("计数 = %d (%v), 期望 %d", count, err, len(all))
+			}
+		})
+	}
+
+	if _, err := storage.Query(RecordFilter{TaskID: "../x"}); err == nil {
+		t.Fatal("非法任务 ID 应返回错误")
+	}
+}
+
+// TestMemoryStorageCapacity 测试按任务与全局容量淘汰最早写入的记录
+func TestMemoryStorageCapacity(t *testing.T) {
+	storage := NewMemoryStorage(3, 5)
+	base := time.Now()
+	for i := range 5 {
+		saveMemoryRecord(t, storage, "a", base.Add(time.Duration(i)*time.Second), true)
+	}
+	if count, _ := storage.Count(RecordFilter{TaskID: "a"}); count != 3 {
+		t.Fatalf("任务容量应为 3, 实际 %d", count)
+	}
+
+	for i := range 3 {
+		saveMemoryRecord(t, storage, "b", base.Add(time.Duration(10+i)*time.Second), true)
+	}
+	records, _ := storage.Query(RecordFilter{TaskID: "a"})
+	if len(records) != 2 || !records[1].StartTime.Equal(base.Add(3*time.Second)) {
+		t.Fatalf("全局容量应淘汰任务 a 最早的记录: %+v", records)
+	}
+	if count, _ := storage.Count(RecordFilter{}); count != 5 {
+		t.Fatalf("全局容量应为 5, 实际 %d", count)
+	}
+
+	// 全局淘汰清空某个任务后，该任务仍可继续写入
+	single := NewMemoryStorage(1, 1)
+	saveMemoryRecord(t, single, "a", base, true)
+	saveMemoryRecord(t, single, "b", base, true)
+	saveMemoryRecord(t, single, "a", base, true)
+	if count, _ := single.Count(RecordFilter{}); count != 1 {
+		t.Fatalf("全局容量应为 1, 实际 %d", count)
+	}
+
+	// 大量写入后写入顺序队列不随写入次数增长
+	for i := range 10000 {
+		saveMemoryRecord(t, storage, "c", base.Add(time.Duration(i)*time.Millisecond), true)
+	}
+	if len(storage.order) > 64 {
+		t.Fatalf("写入顺序队列未压缩: %d", len(storage.order))
+	}
+}
+
+// TestMemoryStorageDelete 测试删除早于指定时间的记录后容量与淘汰顺序保持正确
+func TestMemoryStorageDelete(t *testing.T) {
+	storage := NewMemoryStorage(10, 4)
+	base := time.Now()
+	saveMemoryRecord(t, storage, "a", base, true)
+	saveMemoryRecord(t, storage, "a", base.Add(-time.Hour), true) // 乱序写入，位于缓冲区中间
+	saveMemoryRecord(t, storage, "a", base.Add(time.Second), true)
+	saveMemoryRecord(t, storage, "b", base.Add(-2*time.Hour), false)
+
+	deleted, err := storage.Delete(base.Add(-time.Minute))
+	if err != nil || deleted != 2 {
+		t.Fatalf("删除数量 = %d (%v), 期望 2", deleted, err)
+	}
+	if count, _ := storage.Count(RecordFilter{}); count != 2 {
+		t.Fatalf("剩余记录数 = %d, 期望 2", count)
+	}
+
+	// 删除后剩余容量可继续写入，再次超出时淘汰最早写入的记录
+	saveMemoryRecord(t, storage, "b", base.Add(2*time.Second), true)
+	saveMemoryRecord(t, storage, "b", base.Add(3*time.Second), true)
+	saveMemoryRecord(t, storage, "b", base.Add(4*time.Second), true)
+	records, _ := storage.Query(RecordFilter{TaskID: "a"})
+	if len(records) != 1 || !records[0].StartTime.Equal(base.Add(time.Second)) {
+		t.Fatalf("应淘汰任务 a 最早写入的记录: %+v", records)
+	}
+}
+
+// TestMemoryStorageCopies 测试保存与查询均使用副本，调用方修改不影响已保存的数据
+func TestMemoryStorageCopies(t *testing.T) {
+	storage := NewMemoryStorage(0, 0)
+	record := &ExecutionRecord{TaskID: "a", StartTime: time.Now(), Resources: &ResourceUsage{AllocBytes: 1}}
+	if err := storage.Save(record); err != nil {
+		t.Fatalf("保存记录失败: %v", err)
+	}
+	record.Success = true
+	record.Resources.AllocBytes = 2
+
+	records, _ := storage.Query(RecordFilter{})
+	records[0].Error = "changed"
+	records, _ = storage.Query(RecordFilter{})
+	if records[0].Success || records[0].Error != "" || records[0].Resources.AllocBytes != 1 {
+		t.Fatalf("已保存的记录被修改: %+v", records[0])
+	}
+
+	if err := storage.Save(nil); err == nil {
+		t.Fatal("保存 nil 记录应返回错误")
+	}
+	if err := storage.Save(&ExecutionRecord{TaskID: " "}); err == nil {
+		t.Fatal("空任务 ID 应返回错误")
+	}
+}
+
+// TestMemoryStorageWithRecorder 测试内存存储可直接用于 HistoryRecorder，关闭后记录仍可查询
+func TestMemoryStorageWithRecorder(t *testing.T) {
+	storage := NewMemoryStorage(0, 0)
+	recorder, err := NewHistoryRecorder(storage)
+	if err != nil {
+		t.Fatalf("创建记录器失败: %v", err)
+	}
+	recorder.Record("task", time.Now(), time.Now(), false, 1, errors.New("boom"))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("关闭记录器失败: %v", err)
+	}
+
+	records, err := storage.Query(RecordFilter{TaskID: "task", FailedOnly: true})
+	if err != nil || len(records) != 1 || records[0].Error != "boom" || records[0].RetryCount != 1 {
+		t.Fatalf("记录器写入的记录 = %+v (%v)", records, err)
+	}
+}
+
+func BenchmarkMemoryStorageSave(b *testing.B) {
+	storage := NewMemoryStorage(100, 1000)
+	record := &ExecutionRecord{TaskID: "task", StartTime: time.Now(), Success: true}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		record.TaskID = fmt.Sprintf("task-%d", i%50)
+		_ = storage.Save(record)
+	}
+}
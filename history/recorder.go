@@ -1,6 +1,7 @@
 package history
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -19,6 +20,9 @@ type HistoryRecorder struct {
 	closed  bool
 	mu      sync.Mutex
 	logger  Logger // 可选的日志记录器，用于记录存储失败等非致命错误
+
+	healthMu sync.Mutex
+	health   RecorderHealth // 写入状态，不含队列长度
 }
 
 // RecorderOption 定义 HistoryRecorder 的配置选项
@@ -85,30 +89,35 @@ func (hr *HistoryRecorder) safeWarn(msg string, keysAndValues ...any) {
 
 // Record 记录任务执行结果（异步）
 func (hr *HistoryRecorder) Record(taskID string, startTime, endTime time.Time, success bool, retryCount int, err error) {
-	hr.mu.Lock()
-	if hr.closed {
-		hr.mu.Unlock()
-		return
-	}
-	hr.opsWg.Add(1)
-
-	// 生成记录ID
-	recordID := fmt.Sprintf("%s_%d", taskID, startTime.UnixNano())
-
-	// 构建记录
 	record := &ExecutionRecord{
-		ID:         recordID,
 		TaskID:     taskID,
 		StartTime:  startTime,
 		EndTime:    endTime,
-		Duration:   endTime.Sub(startTime),
 		Success:    success,
 		RetryCount: retryCount,
 	}
-
 	if err != nil {
 		record.Error = err.Error()
 	}
+	hr.RecordExecution(record)
+}
+
+// RecordExecution 记录完整的执行记录（异步），ID 与 Duration 为空时自动补全
+func (hr *HistoryRecorder) RecordExecution(record *ExecutionRecord) {
+	hr.mu.Lock()
+	if hr.closed {
+		hr.mu.Unlock()
+		return
+	}
+	hr.opsWg.Add(1)
+
+	// 生成记录ID
+	if record.ID == "" {
+		record.ID = fmt.Sprintf("%s_%d", record.TaskID, record.StartTime.UnixNano())
+	}
+	if record.Duration == 0 {
+		record.Duration = record.EndTime.Sub(record.StartTime)
+	}
 
 	// 异步写入队列
 	select {
@@ -120,10 +129,11 @@ func (hr *HistoryRecorder) Record(taskID string, startTime, endTime time.Time, s
 		hr.mu.Unlock()
 		defer hr.opsWg.Done()
 		// 队列已满，同步写入（避免丢失数据）
-		if err := hr.storage.Save(record); err != nil {
+		if err := hr.save(record); err != nil {
 			hr.safeWarn("队列已满，同步保存历史记录失败",
-				"taskID", record.TaskID,
-				"recordID", record.ID,
+				"task_id", record.TaskID,
+				"execution_id", record.ID,
+				"duration", record.Duration,
 				"error", err.Error())
 		}
 	}
@@ -134,6 +144,15 @@ func (hr *HistoryRecorder) Query(filter RecordFilter) ([]*ExecutionRecord, error
 	return hr.storage.Query(filter)
 }
 
+// Iterate 逐条遍历历史记录，存储未实现 RecordIterator 时返回 ErrIterationUnsupported
+func (hr *HistoryRecorder) Iterate(ctx context.Context, filter RecordFilter, fn func(record *ExecutionRecord, cursor string) error) error {
+	iterator, ok := hr.storage.(RecordIterator)
+	if !ok {
+		return ErrIterationUnsupported
+	}
+	return iterator.Iterate(ctx, filter, fn)
+}
+
 // Count 统计记录数量
 func (hr *HistoryRecorder) Count(filter RecordFilter) (int, error) {
 	return hr.storage.Count(filter)
@@ -172,11 +191,41 @@ func (hr *HistoryRecorder) writeLoop() {
 	defer hr.wg.Done()
 
 	for record := range hr.queue {
-		if err := hr.storage.Save(record); err != nil {
+		if err := hr.save(record); err != nil {
 			hr.safeWarn("异步保存历史记录失败",
-				"taskID", record.TaskID,
-				"recordID", record.ID,
+				"task_id", record.TaskID,
+				"execution_id", record.ID,
+				"duration", record.Duration,
 				"error", err.Error())
 		}
 	}
 }
+
+// save 写入存储并更新写入状态
+func (hr *HistoryRecorder) save(record *ExecutionRecord) error {
+	err := hr.storage.Save(record)
+	now := time.Now()
+
+	hr.healthMu.Lock()
+	defer hr.healthMu.Unlock()
+	if err != nil {
+		hr.health.LastError = err.Error()
+		hr.health.LastErrorTime = now
+		hr.health.ConsecutiveFailures++
+		return err
+	}
+	hr.health.LastWrite = now
+	hr.health.ConsecutiveFailures = 0
+	return nil
+}
+
+// Health 返回写入队列积压与最近的写入结果
+func (hr *HistoryRecorder) Health() RecorderHealth {
+	hr.healthMu.Lock()
+	health := hr.health
+	hr.healthMu.Unlock()
+
+	health.QueueLength = len(hr.queue)
+	health.QueueCapacity = cap(hr.queue)
+	return health
+}
@@ -1,7 +1,11 @@
 package history
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"sync"
 	"testing"
@@ -533,3 +537,154 @@ func TestRecorderWithoutLogger(t *testing.T) {
 		t.Errorf("期望 1 条记录，得到 %d 条", len(records))
 	}
 }
+
+// TestHistoryRecorderRecordExecution 测试写入带计划时间与延迟的完整记录，并补全 ID 与耗时
+func TestHistoryRecorderRecordExecution(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("创建存储失败: %v", err)
+	}
+	defer cleanupStorage(t, storage)
+
+	recorder, err := NewHistoryRecorder(storage)
+	if err != nil {
+		t.Fatalf("创建记录器失败: %v", err)
+	}
+
+	var _ ExecutionRecorder = recorder
+	scheduled := time.Now().Truncate(time.Second)
+	start := scheduled.Add(1500 * time.Millisecond)
+	recorder.RecordExecution(&ExecutionRecord{
+		TaskID:        "lag-task",
+		StartTime:     start,
+		EndTime:       start.Add(time.Second),
+		Success:       true,
+		ScheduledTime: scheduled,
+		Lag:           1500 * time.Millisecond,
+	})
+	cleanupRecorder(t, recorder)
+
+	records, err := storage.Query(RecordFilter{TaskID: "lag-task"})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("查询失败: %v (%d 条)", err, len(records))
+	}
+	record := records[0]
+	if record.ID == "" || record.Duration != time.Second {
+		t.Errorf("ID / Duration 未补全: %+v", record)
+	}
+	if !record.ScheduledTime.Equal(scheduled) || record.Lag != 1500*time.Millisecond {
+		t.Errorf("计划时间或延迟未保存: %+v", record)
+	}
+}
+
+// flakyStorage 可切换 Save 是否失败的存储
+type flakyStorage struct {
+	Storage
+	mu   sync.Mutex
+	fail bool
+}
+
+func (s *flakyStorage) setFail(fail bool) {
+	s.mu.Lock()
+	s.fail = fail
+	s.mu.Unlock()
+}
+
+func (s *flakyStorage) Save(record *ExecutionRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("disk full")
+	}
+	return nil
+}
+
+func (s *flakyStorage) Close() error { return nil }
+
+// TestHistoryRecorderHealth 测试写入失败计数、最近错误与成功后清零
+func TestHistoryRecorderHealth(t *testing.T) {
+	storage := &flakyStorage{fail: true}
+	recorder, err := NewHistoryRecorder(storage)
+	if err != nil {
+		t.Fatalf("创建记录器失败: %v", err)
+	}
+	defer cleanupRecorder(t, recorder)
+
+	var _ HealthReporter = recorder
+	if health := recorder.Health(); health.QueueCapacity != 100 || health.ConsecutiveFailures != 0 {
+		t.Fatalf("unexpected initial health: %+v", health)
+	}
+
+	now := time.Now()
+	waitHealth := func(cond func(RecorderHealth) bool) RecorderHealth {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			health := recorder.Health()
+			if cond(health) {
+				return health
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("health condition not met: %+v", health)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	for range 3 {
+		recorder.Record("task", now, now, true, 0, nil)
+	}
+	health := waitHealth(func(h RecorderHealth) bool { return h.ConsecutiveFailures == 3 })
+	if health.LastError != "disk full" || health.LastErrorTime.IsZero() || !health.LastWrite.IsZero() {
+		t.Fatalf("unexpected failing health: %+v", health)
+	}
+
+	storage.setFail(false)
+	recorder.Record("task", now, now, true, 0, nil)
+	health = waitHealth(func(h RecorderHealth) bool { return h.ConsecutiveFailures == 0 })
+	if health.LastWrite.IsZero() || health.LastError != "disk full" {
+		t.Fatalf("success should reset failures but keep last error: %+v", health)
+	}
+}
+
+// TestRecorderSlogLogger 测试 *slog.Logger 可直接作为记录器日志，写入失败时输出结构化属性
+func TestRecorderSlogLogger(t *testing.T) {
+	var buf syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	recorder, err := NewHistoryRecorder(&flakyStorage{fail: true}, WithRecorderLogger(logger))
+	if err != nil {
+		t.Fatalf("创建记录器失败: %v", err)
+	}
+
+	now := time.Now()
+	recorder.RecordExecution(&ExecutionRecord{ID: "task_1", TaskID: "task", StartTime: now, EndTime: now.Add(time.Second)})
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("关闭记录器失败: %v", err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &entry); err != nil {
+		t.Fatalf("日志不是 JSON: %v (%q)", err, buf.String())
+	}
+	if entry["task_id"] != "task" || entry["execution_id"] != "task_1" || entry["error"] != "disk full" || entry["duration"] == nil {
+		t.Fatalf("unexpected log attributes: %v", entry)
+	}
+}
+
+// syncBuffer 并发安全的日志缓冲区
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
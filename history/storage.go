@@ -1,7 +1,7 @@
 package history
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -27,19 +27,21 @@ func normalizeStorageTaskID(taskID string) (string, error) {
 }
 
 // Logger 日志接口，用于记录存储层的非致命错误
-// 实现此接口的类型可以接收文件读取、解析等操作中的错误信息
+// 实现此接口的类型可以接收文件读取、解析等操作中的错误信息。
+// *slog.Logger 直接满足该接口，键值对使用 task_id、execution_id、duration、error 等与调度器一致的属性名
 type Logger interface {
 	// Warn 记录警告级别的日志
 	Warn(msg string, keysAndValues ...any)
 }
 
 // FileStorage 基于文件系统的历史记录存储
-// 存储结构：<baseDir>/<taskID>/<date>.jsonl
-// 每行一条 JSON 记录，避免数组追加的锁竞争和尾部损坏
+// 存储结构：<baseDir>/<taskID>/<date>.jsonl，旁边的 <date>.idx 为该文件的行索引
+// 每行一条 JSON 记录，避免数组追加的锁竞争和尾部损坏；查询与计数通过索引定位，只解码返回的记录
 type FileStorage struct {
 	baseDir string
 	logger  Logger // 可选的日志记录器，用于记录非致命错误
 	mu      sync.RWMutex
+	indexMu sync.Mutex // 串行化读取时的索引修复
 }
 
 // Option 定义 FileStorage 的配置选项
@@ -106,14 +108,28 @@ func (fs *FileStorage) Save(record *ExecutionRecord) error {
 	if err != nil {
 		return fmt.Errorf("failed to open history file: %w", err)
 	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat history file: %w", err)
+	}
 
-	if _, err := file.Write(append(data, '\n')); err != nil {
+	line := append(data, '\n')
+	if _, err := file.Write(line); err != nil {
 		_ = file.Close()
 		return fmt.Errorf("failed to append history record: %w", err)
 	}
 	if err := file.Close(); err != nil {
 		return fmt.Errorf("failed to close history file: %w", err)
 	}
+
+	// 索引写入失败不影响记录本身，读取时会按数据文件补齐
+	entry := newIndexEntry(record.StartTime, record.Success, info.Size(), len(line))
+	if err := appendIndex(filePath, entry); err != nil {
+		safeWarn(fs.logger, "写入历史记录索引失败",
+			"file", indexPath(filePath),
+			"error", err.Error())
+	}
 	return nil
 }
 
@@ -129,92 +145,45 @@ func safeWarn(logger Logger, msg string, keysAndValues ...any) {
 	logger.Warn(msg, keysAndValues...)
 }
 
-func closeFile(file *os.File) error {
-	if file == nil {
-		return nil
-	}
-	return file.Close()
-}
-
-// Query 根据过滤器查询记录
+// Query 根据过滤器查询记录，按开始时间倒序返回。
+// 通过 Iterate 从新到旧读取日期文件，已返回 Offset+Limit 条记录后不再读取更早的文件，只解码返回的记录
 func (fs *FileStorage) Query(filter RecordFilter) ([]*ExecutionRecord, error) {
-	fs.mu.RLock()
-	defer fs.mu.RUnlock()
-
-	var allRecords []*ExecutionRecord
-
-	taskDirs, err := fs.getTaskDirs(filter.TaskID)
+	filter.Cursor = ""
+	records := []*ExecutionRecord{}
+	err := fs.Iterate(context.Background(), filter, func(record *ExecutionRecord, _ string) error {
+		records = append(records, record)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	for _, taskDir := range taskDirs {
-		dateFiles, err := fs.getDateFiles(taskDir, filter.StartTime, filter.EndTime)
-		if err != nil {
-			safeWarn(fs.logger, "无法读取任务目录下的日期文件",
-				"taskDir", taskDir,
-				"error", err.Error())
-			continue
-		}
-
-		for _, dateFile := range dateFiles {
-			records, err := fs.readRecordsFromFile(dateFile)
-			if err != nil {
-				safeWarn(fs.logger, "读取历史记录文件失败",
-					"file", dateFile,
-					"error", err.Error())
-				continue
-			}
-			for _, record := range records {
-				if fs.matchFilter(record, filter) {
-					allRecords = append(allRecords, record)
-				}
-			}
-		}
-	}
-
-	sort.Slice(allRecords, func(i, j int) bool {
-		return allRecords[i].StartTime.After(allRecords[j].StartTime)
-	})
-
-	if filter.Offset > 0 && filter.Offset < len(allRecords) {
-		allRecords = allRecords[filter.Offset:]
-	} else if filter.Offset >= len(allRecords) {
-		return []*ExecutionRecord{}, nil
-	}
-
-	if filter.Limit > 0 && filter.Limit < len(allRecords) {
-		allRecords = allRecords[:filter.Limit]
-	}
-
-	return allRecords, nil
+	return records, nil
 }
 
-// Count 统计符合条件的记录数量
+// Count 统计符合条件的记录数量，只读取索引
 func (fs *FileStorage) Count(filter RecordFilter) (int, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
 
-	taskDirs, err := fs.getTaskDirs(filter.TaskID)
+	days, err := fs.getDays(filter, false)
 	if err != nil {
 		return 0, err
 	}
 
 	total := 0
-	for _, taskDir := range taskDirs {
-		dateFiles, err := fs.getDateFiles(taskDir, filter.StartTime, filter.EndTime)
-		if err != nil {
-			continue
-		}
-		for _, dateFile := range dateFiles {
-			count, err := fs.countRecordsInFile(dateFile, filter)
+	for _, day := range days {
+		for _, dateFile := range day.files {
+			entries, err := fs.loadIndex(dateFile)
 			if err != nil {
 				continue
 			}
-			total += count
+			for _, entry := range entries {
+				if entry.match(filter) {
+					total++
+				}
+			}
 		}
 	}
-
 	return total, nil
 }
 
@@ -235,7 +204,7 @@ func (fs *FileStorage) Delete(before time.Time) (int, error) {
 		entries, err := os.ReadDir(taskDir)
 		if err != nil {
 			safeWarn(fs.logger, "无法读取任务目录以进行删除操作",
-				"taskDir", taskDir,
+				"task_dir", taskDir,
 				"error", err.Error())
 			continue
 		}
@@ -247,15 +216,16 @@ func (fs *FileStorage) Delete(before time.Time) (int, error) {
 			dateStr := strings.TrimSuffix(entry.Name(), ".jsonl")
 			if dateStr < beforeDate {
 				filePath := filepath.Join(taskDir, entry.Name())
-				records, err := fs.readRecordsFromFile(filePath)
+				entries, err := fs.loadIndex(filePath)
 				if err == nil {
-					deletedCount += len(records)
+					deletedCount += countReadable(entries)
 				} else {
 					safeWarn(fs.logger, "删除前读取记录数失败，将继续删除文件",
 						"file", filePath,
 						"error", err.Error())
 				}
 				_ = os.Remove(filePath)
+				_ = os.Remove(indexPath(filePath))
 			}
 		}
 
@@ -272,41 +242,6 @@ func (fs *FileStorage) Close() error { return nil }
 
 // 辅助方法
 
-func (fs *FileStorage) readRecordsFromFile(filePath string) ([]*ExecutionRecord, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
-	}
-
-	var records []*ExecutionRecord
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		var rec ExecutionRecord
-		if err := json.Unmarshal([]byte(line), &rec); err != nil {
-			safeWarn(fs.logger, "解析历史记录 JSON 失败，跳过该行",
-				"file", filePath,
-				"line", lineNum,
-				"error", err.Error())
-			continue
-		}
-		records = append(records, &rec)
-	}
-	if err := scanner.Err(); err != nil {
-		_ = closeFile(file)
-		return nil, err
-	}
-	if err := closeFile(file); err != nil {
-		return nil, err
-	}
-	return records, nil
-}
-
 func (fs *FileStorage) getTaskDirs(taskID string) ([]string, error) {
 	if taskID != "" {
 		normalizedTaskID, err := normalizeStorageTaskID(taskID)
@@ -334,35 +269,55 @@ func (fs *FileStorage) getTaskDirs(taskID string) ([]string, error) {
 	return dirs, nil
 }
 
-func (fs *FileStorage) countRecordsInFile(filePath string, filter RecordFilter) (int, error) {
-	file, err := os.Open(filePath)
+// dayFiles 同一日期下各任务的数据文件
+type dayFiles struct {
+	date  string
+	files []string
+}
+
+// getDays 返回过滤条件涉及的日期文件，按日期倒序分组；warn 为 true 时记录无法读取的任务目录
+func (fs *FileStorage) getDays(filter RecordFilter, warn bool) ([]dayFiles, error) {
+	taskDirs, err := fs.getTaskDirs(filter.TaskID)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	count := 0
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-		var rec ExecutionRecord
-		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+	byDate := make(map[string][]string)
+	for _, taskDir := range taskDirs {
+		dateFiles, err := fs.getDateFiles(taskDir, filter.StartTime, filter.EndTime)
+		if err != nil {
+			if warn {
+				safeWarn(fs.logger, "无法读取任务目录下的日期文件",
+					"task_dir", taskDir,
+					"error", err.Error())
+			}
 			continue
 		}
-		if fs.matchFilter(&rec, filter) {
-			count++
+		for _, dateFile := range dateFiles {
+			date := strings.TrimSuffix(filepath.Base(dateFile), ".jsonl")
+			byDate[date] = append(byDate[date], dateFile)
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		_ = closeFile(file)
-		return 0, err
+
+	days := make([]dayFiles, 0, len(byDate))
+	for date, files := range byDate {
+		days = append(days, dayFiles{date: date, files: files})
 	}
-	if err := closeFile(file); err != nil {
-		return 0, err
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].date > days[j].date
+	})
+	return days, nil
+}
+
+// countReadable 统计索引中可解析的记录数
+func countReadable(entries []indexEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if entry.flags&indexUnreadable == 0 {
+			count++
+		}
 	}
-	return count, nil
+	return count
 }
 
 func (fs *FileStorage) getDateFiles(taskDir string, startTime, endTime *time.Time) ([]string, error) {
@@ -395,7 +350,8 @@ func (fs *FileStorage) isDateInRange(dateStr string, startTime, endTime *time.Ti
 	return true
 }
 
-func (fs *FileStorage) matchFilter(record *ExecutionRecord, filter RecordFilter) bool {
+// matchFilter 判断记录是否满足过滤条件（不含分页）
+func matchFilter(record *ExecutionRecord, filter RecordFilter) bool {
 	if filter.TaskID != "" && record.TaskID != filter.TaskID {
 		return false
 	}
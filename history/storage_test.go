@@ -506,8 +506,14 @@ func TestFileStorageSharding(t *testing.T) {
 		t.Fatalf("读取任务目录失败: %v", err)
 	}
 
-	if len(entries) != 4 {
-		t.Errorf("期望 4 个日期文件，得到 %d 个", len(entries))
+	dateFiles := 0
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) == ".jsonl" {
+			dateFiles++
+		}
+	}
+	if dateFiles != 4 {
+		t.Errorf("期望 4 个日期文件，得到 %d 个", dateFiles)
 	}
 
 	// 查询所有记录
@@ -1,6 +1,7 @@
 package history
 
 import (
+	"context"
 	"time"
 )
 
@@ -14,6 +15,18 @@ type ExecutionRecord struct {
 	Success    bool          `json:"success"`    // 是否成功
 	RetryCount int           `json:"retryCount"` // 重试次数
 	Error      string        `json:"error"`      // 错误信息（如果失败）
+
+	ScheduledTime time.Time     `json:"scheduledTime,omitempty"` // 计划触发时间，手动触发时为空
+	Lag           time.Duration `json:"lag,omitempty"`           // 实际开始相对计划时间的延迟（纳秒）
+
+	Resources *ResourceUsage `json:"resources,omitempty"` // 资源采样，未被采样时为空
+}
+
+// ResourceUsage 单次执行的资源采样
+type ResourceUsage struct {
+	WallTime      time.Duration `json:"wallTime"`      // 各次尝试的执行耗时合计，不含重试等待（纳秒）
+	AllocBytes    uint64        `json:"allocBytes"`    // 执行期间的堆分配字节数（近似值），仅 AllocMeasured 时有效
+	AllocMeasured bool          `json:"allocMeasured"` // 执行期间没有其他任务运行，分配量可归属于该任务
 }
 
 // RecordFilter 查询过滤器
@@ -25,6 +38,7 @@ type RecordFilter struct {
 	FailedOnly  bool       // 仅查询失败的记录
 	Limit       int        // 返回记录数限制（0表示不限制）
 	Offset      int        // 偏移量（分页）
+	Cursor      string     // 游标（可选），仅 RecordIterator 使用：从游标对应的记录之后继续遍历
 }
 
 // Storage 定义历史记录存储接口
@@ -45,6 +59,14 @@ type Storage interface {
 	Close() error
 }
 
+// RecordIterator 可选接口：按开始时间倒序逐条遍历记录，用于导出大量历史记录与可恢复的分页。
+// fn 收到的 cursor 为不透明的游标，放入 RecordFilter.Cursor 即可从该记录之后继续；
+// Offset / Limit 作用于游标之后的记录。fn 返回错误或 ctx 取消时停止遍历并返回该错误，
+// 游标无法解析时返回 ErrInvalidCursor。FileStorage、MemoryStorage 与 HistoryRecorder 实现了该接口
+type RecordIterator interface {
+	Iterate(ctx context.Context, filter RecordFilter, fn func(record *ExecutionRecord, cursor string) error) error
+}
+
 // Recorder 定义历史记录器接口
 type Recorder interface {
 	// Record 记录任务执行结果
@@ -62,3 +84,24 @@ type Recorder interface {
 	// Close 关闭记录器
 	Close() error
 }
+
+// ExecutionRecorder 可选接口：直接写入完整的执行记录，携带计划时间、调度延迟等扩展字段。
+// 调度器优先使用该接口，未实现时回退到 Recorder.Record。
+type ExecutionRecorder interface {
+	RecordExecution(record *ExecutionRecord)
+}
+
+// RecorderHealth 历史记录器的写入状态
+type RecorderHealth struct {
+	QueueLength         int       `json:"queueLength"`         // 等待写入的记录数
+	QueueCapacity       int       `json:"queueCapacity"`       // 写入队列容量
+	LastWrite           time.Time `json:"lastWrite"`           // 最近一次写入成功时间
+	LastError           string    `json:"lastError,omitempty"` // 最近一次写入错误
+	LastErrorTime       time.Time `json:"lastErrorTime"`       // 最近一次写入错误时间
+	ConsecutiveFailures int       `json:"consecutiveFailures"` // 连续写入失败次数，成功写入后清零
+}
+
+// HealthReporter 可选接口：报告记录器的写入状态，供 Cron.Health 判断历史存储是否持续失败
+type HealthReporter interface {
+	Health() RecorderHealth
+}
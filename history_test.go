@@ -632,7 +632,13 @@ func TestFileStorageSharding(t *testing.T) {
 		t.Fatalf("Failed to read task directory: %v", err)
 	}
 
-	if len(files) != 4 {
-		t.Errorf("Expected 4 date files, got %d", len(files))
+	dateFiles := 0
+	for _, file := range files {
+		if filepath.Ext(file.Name()) == ".jsonl" {
+			dateFiles++
+		}
+	}
+	if dateFiles != 4 {
+		t.Errorf("Expected 4 date files, got %d", dateFiles)
 	}
 }
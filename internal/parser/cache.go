@@ -1,12 +1,13 @@
 package parser
 
 import (
-	"container/list"
 	"fmt"
+	"hash/maphash"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,142 +16,242 @@ var (
 	ErrInvalidSpec = ErrUnsupportedSpec // 兼容原有错误
 )
 
-// 缓存大小限制，避免内存无限增长
-var maxCacheSize = 1000
+// DefaultCacheSize 每种解析选项默认缓存的表达式数量
+const DefaultCacheSize = 1000
 
-// parserCache 提供了一个线程安全的表达式解析结果缓存
-// 使用 LRU (最近最少使用) 算法管理缓存
-type parserCache struct {
-	cache       map[string]Schedule // 表达式到解析结果的映射
-	parserType  Parser              // 解析器类型
-	accessOrder *list.List          // 访问顺序，用于LRU淘汰（队头最旧，队尾最新）
-	accessIndex map[string]*list.Element
-	mu          sync.RWMutex // 读写锁，保护缓存
+// 缓存容量，0 表示禁用缓存；通过 SetCacheSize 调整
+var maxCacheSize atomic.Int64
+
+func init() {
+	maxCacheSize.Store(DefaultCacheSize)
 }
 
-// 全局缓存实例，按解析器选项类型分别缓存
+// CacheStats 解析缓存统计信息，计数在调整容量后保留
+type CacheStats struct {
+	Hits      uint64 // 命中次数
+	Misses    uint64 // 未命中次数（含解析失败）
+	Evictions uint64 // 因容量不足淘汰的条目数
+	Entries   int    // 当前缓存的条目数（所有解析选项合计）
+	Capacity  int    // 每种解析选项的缓存容量，0 表示禁用
+}
+
+// 全局缓存统计
 var (
-	parseCaches     = make(map[ParseOption]*parserCache)
-	parseCachesLock sync.RWMutex
+	cacheHits      atomic.Uint64
+	cacheMisses    atomic.Uint64
+	cacheEvictions atomic.Uint64
 )
 
-// getCacheForParser 获取或创建特定解析器类型的缓存
-func getCacheForParser(p Parser) *parserCache {
-	parseCachesLock.RLock()
-	cache, exists := parseCaches[p.options]
-	parseCachesLock.RUnlock()
-
-	if !exists {
-		parseCachesLock.Lock()
-		// 双重检查，避免竞态条件
-		if cache, exists = parseCaches[p.options]; !exists {
-			cache = &parserCache{
-				cache:       make(map[string]Schedule),
-				parserType:  p,
-				accessOrder: list.New(),
-				accessIndex: make(map[string]*list.Element),
-			}
-			parseCaches[p.options] = cache
-		}
-		parseCachesLock.Unlock()
-	}
+// parserCache 提供了一个线程安全的表达式解析结果缓存。
+// 按表达式哈希分片，每个分片使用 CLOCK（二次机会）近似 LRU 淘汰：
+// 命中只需分片读锁并原子标记访问位，不会争抢写锁。
+type parserCache struct {
+	seed   maphash.Seed
+	shards []*cacheShard
+}
 
-	return cache
+// cacheShard 缓存分片
+type cacheShard struct {
+	mu       sync.RWMutex
+	entries  map[string]*cacheEntry
+	ring     []*cacheEntry // CLOCK 环，长度不超过 capacity
+	hand     int           // CLOCK 指针，指向下一个淘汰候选
+	capacity int
 }
 
-// parseWithCache 尝试从缓存中获取解析结果，如果不存在则解析并缓存
-func parseWithCache(p Parser, spec string) (Schedule, error) {
-	// 获取该解析器的缓存
-	cache := getCacheForParser(p)
+// cacheEntry 缓存条目，referenced 为 CLOCK 访问位
+type cacheEntry struct {
+	spec       string
+	schedule   Schedule
+	referenced atomic.Bool
+}
 
-	// 尝试从缓存中读取
-	cache.mu.RLock()
-	if schedule, found := cache.cache[spec]; found {
-		// 更新访问记录（需要升级为写锁）
-		cache.mu.RUnlock()
-
-		// 获取写锁并更新访问顺序
-		cache.mu.Lock()
-		// 再次检查，因为可能在获取写锁期间已被其他协程修改
-		if scheduleLocked, stillExists := cache.cache[spec]; stillExists {
-			// 将此项移到访问顺序的末尾（最新访问）
-			cache.updateAccessOrder(spec)
-			schedule = scheduleLocked
-		}
-		cache.mu.Unlock()
+// 全局缓存实例，按解析器选项类型分别缓存（ParseOption -> *parserCache）
+var (
+	parseCaches     sync.Map
+	parseCachesLock sync.Mutex // 串行化缓存创建与容量调整
+)
 
-		return schedule, nil
+// SetCacheSize 设置每种解析选项缓存的表达式数量，n <= 0 时禁用缓存。
+// 调整容量会清空已缓存的解析结果，统计计数保留。
+func SetCacheSize(n int) {
+	parseCachesLock.Lock()
+	defer parseCachesLock.Unlock()
+
+	maxCacheSize.Store(int64(max(n, 0)))
+	parseCaches.Clear()
+}
+
+// CacheSize 返回当前每种解析选项的缓存容量
+func CacheSize() int {
+	return int(maxCacheSize.Load())
+}
+
+// GetCacheStats 返回解析缓存的命中、未命中、淘汰计数与当前条目数
+func GetCacheStats() CacheStats {
+	stats := CacheStats{
+		Hits:      cacheHits.Load(),
+		Misses:    cacheMisses.Load(),
+		Evictions: cacheEvictions.Load(),
+		Capacity:  CacheSize(),
 	}
-	cache.mu.RUnlock()
+	parseCaches.Range(func(_, value any) bool {
+		stats.Entries += value.(*parserCache).len()
+		return true
+	})
+	return stats
+}
 
-	// 缓存未命中，解析表达式
-	schedule, err := p.parseNoCache(spec)
-	if err != nil {
-		return nil, err
+// cacheShardCount 按容量决定分片数：每片约 64 项，取 2 的幂，最多 64 片
+func cacheShardCount(capacity int) int {
+	count := 1
+	for count*2 <= min(capacity/64, 64) {
+		count *= 2
 	}
+	return count
+}
 
-	// 将结果添加到缓存
-	cache.mu.Lock()
-	defer cache.mu.Unlock()
+// newParserCache 创建指定总容量的分片缓存，余数分给前几片，各片容量之和等于 capacity
+func newParserCache(capacity int) *parserCache {
+	count := cacheShardCount(capacity)
+	cache := &parserCache{seed: maphash.MakeSeed(), shards: make([]*cacheShard, count)}
+	for i := range cache.shards {
+		perShard := capacity / count
+		if i < capacity%count {
+			perShard++
+		}
+		cache.shards[i] = &cacheShard{
+			entries:  make(map[string]*cacheEntry, perShard),
+			ring:     make([]*cacheEntry, 0, perShard),
+			capacity: perShard,
+		}
+	}
+	return cache
+}
 
-	// 可能有其他协程已写入，直接复用已存在的调度结果并刷新顺序
-	if existing, exists := cache.cache[spec]; exists {
-		cache.updateAccessOrder(spec)
-		return existing, nil
+// getCacheForParser 获取或创建特定解析器类型的缓存，缓存被禁用时返回 nil
+func getCacheForParser(p Parser) *parserCache {
+	if cache, ok := parseCaches.Load(p.options); ok {
+		return cache.(*parserCache)
+	}
+	if maxCacheSize.Load() == 0 {
+		return nil
 	}
 
-	// 检查缓存是否已满
-	if cache.accessOrder.Len() >= maxCacheSize {
-		cache.evictOldest()
+	parseCachesLock.Lock()
+	defer parseCachesLock.Unlock()
+	capacity := int(maxCacheSize.Load())
+	if capacity == 0 {
+		return nil
 	}
+	cache, _ := parseCaches.LoadOrStore(p.options, newParserCache(capacity))
+	return cache.(*parserCache)
+}
 
-	// 添加新项到缓存
-	cache.cache[spec] = schedule
-	cache.addAccessOrder(spec)
+// shard 返回表达式所在的分片
+func (pc *parserCache) shard(spec string) *cacheShard {
+	if len(pc.shards) == 1 {
+		return pc.shards[0]
+	}
+	return pc.shards[maphash.String(pc.seed, spec)&uint64(len(pc.shards)-1)]
+}
 
-	return schedule, nil
+// get 读取缓存，命中时标记访问位
+func (pc *parserCache) get(spec string) (Schedule, bool) {
+	shard := pc.shard(spec)
+	shard.mu.RLock()
+	entry, ok := shard.entries[spec]
+	shard.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if !entry.referenced.Load() {
+		entry.referenced.Store(true)
+	}
+	return entry.schedule, true
 }
 
-// updateAccessOrder 更新访问顺序，将指定项移到访问顺序的末尾
-// 注意：调用前必须获取写锁
-func (pc *parserCache) updateAccessOrder(spec string) {
-	if elem, ok := pc.accessIndex[spec]; ok {
-		pc.accessOrder.MoveToBack(elem)
-		return
+// add 写入缓存；其他协程已写入时返回已有结果，保证同一表达式共享同一个调度对象
+func (pc *parserCache) add(spec string, schedule Schedule) Schedule {
+	shard := pc.shard(spec)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if existing, ok := shard.entries[spec]; ok {
+		existing.referenced.Store(true)
+		return existing.schedule
+	}
+
+	entry := &cacheEntry{spec: spec, schedule: schedule}
+	shard.entries[spec] = entry
+	if len(shard.ring) < shard.capacity {
+		shard.ring = append(shard.ring, entry)
+		return schedule
 	}
 
-	elem := pc.accessOrder.PushBack(spec)
-	pc.accessIndex[spec] = elem
+	// CLOCK 淘汰：跳过并清除被访问过的条目，淘汰第一个未被访问的条目
+	for {
+		candidate := shard.ring[shard.hand]
+		if candidate.referenced.Load() {
+			candidate.referenced.Store(false)
+			shard.hand = (shard.hand + 1) % len(shard.ring)
+			continue
+		}
+		delete(shard.entries, candidate.spec)
+		shard.ring[shard.hand] = entry
+		shard.hand = (shard.hand + 1) % len(shard.ring)
+		cacheEvictions.Add(1)
+		return schedule
+	}
 }
 
-// addAccessOrder 新增访问记录
-func (pc *parserCache) addAccessOrder(spec string) {
-	if elem, ok := pc.accessIndex[spec]; ok {
-		pc.accessOrder.MoveToBack(elem)
-		return
+// len 返回缓存条目数
+func (pc *parserCache) len() int {
+	total := 0
+	for _, shard := range pc.shards {
+		shard.mu.RLock()
+		total += len(shard.entries)
+		shard.mu.RUnlock()
 	}
-	pc.accessIndex[spec] = pc.accessOrder.PushBack(spec)
+	return total
 }
 
-// evictOldest 移除最久未访问的项
-func (pc *parserCache) evictOldest() {
-	oldest := pc.accessOrder.Front()
-	if oldest == nil {
-		return
+// parseWithCache 尝试从缓存中获取解析结果，如果不存在则解析并缓存
+func parseWithCache(p Parser, spec string) (Schedule, error) {
+	cache := getCacheForParser(p)
+	if cache == nil {
+		return p.parseNoCache(spec)
 	}
-	spec := oldest.Value.(string)
-	delete(pc.cache, spec)
-	delete(pc.accessIndex, spec)
-	pc.accessOrder.Remove(oldest)
+
+	if schedule, found := cache.get(spec); found {
+		cacheHits.Add(1)
+		return schedule, nil
+	}
+
+	// 缓存未命中，解析表达式
+	cacheMisses.Add(1)
+	schedule, err := p.parseNoCache(spec)
+	if err != nil {
+		return nil, err
+	}
+	return cache.add(spec, schedule), nil
 }
 
-// 保留原始解析方法，用于缓存未命中时
-func (p Parser) parseNoCache(spec string) (Schedule, error) {
+// 保留原始解析方法，用于缓存未命中时。
+// 返回的 *ParseError 偏移相对于 spec。
+func (p Parser) parseNoCache(spec string) (schedule Schedule, err error) {
 	trimmed := strings.TrimSpace(spec)
 	if len(trimmed) == 0 {
-		return nil, fmt.Errorf("empty spec string")
+		return nil, withSpec(newParseError(ReasonEmpty, "", "empty spec string"), spec, 0)
 	}
 
+	base := strings.Index(spec, trimmed)
+	defer func() {
+		if err != nil {
+			err = withSpec(err, spec, base)
+		}
+	}()
+
 	loc := time.Local
 	explicitLocation := false
 
@@ -165,11 +266,11 @@ func (p Parser) parseNoCache(spec string) (Schedule, error) {
 			prefix = fields[0]
 			rest = strings.TrimSpace(strings.TrimPrefix(trimmed, prefix))
 		} else {
-			return nil, fmt.Errorf("invalid timezone spec: %s", spec)
+			return nil, timezoneError(ReasonEmpty, trimmed, trimmed, "invalid timezone spec: %s", spec)
 		}
 
 		if rest == "" {
-			return nil, fmt.Errorf("missing cron expression after timezone prefix: %s", spec)
+			return nil, timezoneError(ReasonEmpty, trimmed, trimmed, "missing cron expression after timezone prefix: %s", spec)
 		}
 
 		tzName := strings.TrimPrefix(prefix, "TZ=")
@@ -177,10 +278,11 @@ func (p Parser) parseNoCache(spec string) (Schedule, error) {
 
 		location, err := time.LoadLocation(tzName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load timezone %s: %w", tzName, err)
+			return nil, timezoneError(ReasonInvalidTimezone, trimmed, tzName, "failed to load timezone %s: %w", tzName, err)
 		}
 
 		loc = location
+		base += strings.Index(trimmed, rest)
 		trimmed = rest
 		explicitLocation = true
 	}
@@ -188,18 +290,19 @@ func (p Parser) parseNoCache(spec string) (Schedule, error) {
 	// 支持描述符语法（需要Descriptor选项）
 	if strings.HasPrefix(trimmed, "@") {
 		if p.options&Descriptor == 0 {
-			return nil, fmt.Errorf("descriptor syntax not supported without Descriptor option: %s", trimmed)
+			return nil, descriptorError(newParseError(ReasonDescriptorDisabled, "",
+				"descriptor syntax not supported without Descriptor option: %s", trimmed), trimmed)
 		}
 		schedule, err := p.parseDescriptor(trimmed, loc)
 		if err != nil {
-			return nil, err
+			return nil, descriptorError(err, trimmed)
 		}
 		setLocation(schedule, loc, explicitLocation)
 		return schedule, nil
 	}
 
 	// 使用通用的cron字段解析方法
-	schedule, err := p.parseCronFields(trimmed, loc)
+	schedule, err = p.parseCronFields(trimmed, loc)
 	if err != nil {
 		return nil, err
 	}
@@ -242,29 +345,60 @@ func (p Parser) parseDescriptor(spec string, loc *time.Location) (Schedule, erro
 		}
 	}
 
+	// 处理 @businessday(N) / @businessday(L) 语法：每月第N个（最后一个）营业日零点
+	if arg, ok := strings.CutPrefix(spec, "@businessday("); ok {
+		arg, ok = strings.CutSuffix(arg, ")")
+		if !ok || strings.TrimSpace(arg) == "" {
+			return nil, fmt.Errorf("invalid businessday descriptor: %s", spec)
+		}
+		cronSpec = "0 0 BD" + strings.TrimSpace(arg) + " * *"
+		if p.options&Second > 0 {
+			cronSpec = "0 " + cronSpec
+		}
+	}
+
 	if cronSpec != "" {
 		return p.parseCronFields(cronSpec, loc)
 	}
 
+	// 处理 @sunrise(lat,lon[,offset]) / @sunset(lat,lon[,offset]) 语法
+	if strings.HasPrefix(spec, "@sunrise(") || strings.HasPrefix(spec, "@sunset(") {
+		return parseSolar(spec, loc)
+	}
+
 	// 处理 @every 语法
 	if strings.HasPrefix(spec, "@every ") {
 		return p.parseEvery(spec[7:], loc)
 	}
 
-	return nil, fmt.Errorf("unrecognized descriptor: %s", spec)
+	// 处理 @reboot / @start 语法，可带延迟
+	if name, _, _ := strings.Cut(spec, " "); name == "@reboot" || name == "@start" {
+		return parseStartup(spec)
+	}
+
+	return nil, newParseError(ReasonUnknownDescriptor, spec, "unrecognized descriptor: %s", spec)
 }
 
 // parseCronFields 解析标准的cron字段，不处理描述符语法
 func (p Parser) parseCronFields(spec string, loc *time.Location) (Schedule, error) {
-	fields := strings.Fields(spec)
-	if len(fields) == 0 {
-		return nil, fmt.Errorf("empty spec string")
+	userFields := strings.Fields(spec)
+	if len(userFields) == 0 {
+		return nil, newParseError(ReasonEmpty, "", "empty spec string")
 	}
 
-	fields, err := normalizeFields(fields, p.options)
+	fields, err := normalizeFields(append([]string(nil), userFields...), p.options)
 	if err != nil {
 		return nil, err
 	}
+	positions := fieldPositions(len(userFields), p.options)
+	offsets := fieldOffsets(spec)
+	wrap := func(err error, idx int) error {
+		offset := -1
+		if pos := positions[idx]; pos >= 0 && pos < len(offsets) {
+			offset = offsets[pos]
+		}
+		return fieldError(err, idx, positions[idx], offset, fields[idx])
+	}
 
 	var (
 		second     uint64
@@ -293,36 +427,36 @@ func (p Parser) parseCronFields(spec string, loc *time.Location) (Schedule, erro
 		switch place {
 		case Second:
 			if fieldValue, err = getField(fieldSpec, seconds); err != nil {
-				return nil, fmt.Errorf("failed to parse second field: %s", err)
+				return nil, wrap(err, idx)
 			}
 			second = fieldValue
 		case Minute:
 			if fieldValue, err = getField(fieldSpec, minutes); err != nil {
-				return nil, fmt.Errorf("failed to parse minute field: %s", err)
+				return nil, wrap(err, idx)
 			}
 			minute = fieldValue
 		case Hour:
 			if fieldValue, err = getField(fieldSpec, hours); err != nil {
-				return nil, fmt.Errorf("failed to parse hour field: %s", err)
+				return nil, wrap(err, idx)
 			}
 			hour = fieldValue
 		case Dom:
 			// 使用特殊字段解析器处理 L/W/LW 语法
 			domInfo, err = getDomFieldSpecial(fieldSpec, dom)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse day-of-month field: %s", err)
+				return nil, wrap(err, idx)
 			}
 			dayofmonth = domInfo.bits
 		case Month:
 			if fieldValue, err = getField(fieldSpec, months); err != nil {
-				return nil, fmt.Errorf("failed to parse month field: %s", err)
+				return nil, wrap(err, idx)
 			}
 			month = fieldValue
 		case Dow:
 			// 使用特殊字段解析器处理 L/# 语法
 			dowInfo, err = getDowFieldSpecial(fieldSpec, dow)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse day-of-week field: %s", err)
+				return nil, wrap(err, idx)
 			}
 			dayofweek = dowInfo.bits
 		}
@@ -344,6 +478,8 @@ func (p Parser) parseCronFields(spec string, loc *time.Location) (Schedule, erro
 		schedule.lastDayOfMonth = domInfo.lastDayOfMonth
 		schedule.lastWorkdayOfMonth = domInfo.lastWorkdayOfMonth
 		schedule.workdaysOfMonth = domInfo.workdaysOfMonth
+		schedule.businessDaysOfMonth = domInfo.businessDaysOfMonth
+		schedule.lastBusinessDayOfMonth = domInfo.lastBusinessDayOfMonth
 		schedule.daysOfMonthRestricted = domInfo.isRestricted
 	}
 
@@ -383,6 +519,9 @@ func setLocation(schedule Schedule, loc *time.Location, explicit bool) {
 	case *ConstantDelaySchedule:
 		typed.Location = loc
 		typed.locationSet = explicit
+	case *SolarSchedule:
+		typed.Location = loc
+		typed.locationSet = explicit
 	}
 }
 
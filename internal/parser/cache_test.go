@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"container/list"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -9,9 +11,7 @@ import (
 // TestCacheHit 测试缓存命中功能
 func TestCacheHit(t *testing.T) {
 	// 清空全局缓存，确保测试环境干净
-	parseCachesLock.Lock()
-	parseCaches = make(map[ParseOption]*parserCache)
-	parseCachesLock.Unlock()
+	resetCaches(t, DefaultCacheSize)
 
 	// 使用标准解析器
 	p := standardParser
@@ -37,10 +37,7 @@ func TestCacheHit(t *testing.T) {
 	}
 
 	// 验证缓存内容
-	cache := getCacheForParser(p)
-	cache.mu.RLock()
-	cachedSched, exists := cache.cache[expr]
-	cache.mu.RUnlock()
+	cachedSched, exists := cachedSchedule(p, expr)
 
 	if !exists {
 		t.Errorf("表达式 %q 未被缓存", expr)
@@ -53,28 +50,13 @@ func TestCacheHit(t *testing.T) {
 
 // TestCacheLRU 测试LRU淘汰机制
 func TestCacheLRU(t *testing.T) {
-	// 清空全局缓存，确保测试环境干净
-	parseCachesLock.Lock()
-	parseCaches = make(map[ParseOption]*parserCache)
-	parseCachesLock.Unlock()
+	// 使用小容量缓存进行测试，结束后恢复默认容量
+	const testCacheSize = 5
+	resetCaches(t, testCacheSize)
 
 	// 使用标准解析器
 	p := standardParser
 
-	// 创建一个自定义的小容量缓存进行测试
-	const testCacheSize = 5
-
-	// 保存原始的maxCacheSize值
-	origMaxCacheSize := maxCacheSize
-
-	// 修改为测试用的小容量
-	maxCacheSize = testCacheSize
-
-	// 测试结束后恢复原值
-	defer func() {
-		maxCacheSize = origMaxCacheSize
-	}()
-
 	// 创建超过缓存容量的表达式
 	exprs := []string{
 		"*/5 * * * *",
@@ -93,23 +75,18 @@ func TestCacheLRU(t *testing.T) {
 		}
 	}
 
-	// 验证缓存内容
-	cache := getCacheForParser(p)
-	cache.mu.RLock()
-	defer cache.mu.RUnlock()
-
 	// 检查缓存大小
-	if len(cache.cache) > testCacheSize {
-		t.Errorf("缓存大小超过限制: %d > %d", len(cache.cache), testCacheSize)
+	if entries := GetCacheStats().Entries; entries > testCacheSize {
+		t.Errorf("缓存大小超过限制: %d > %d", entries, testCacheSize)
 	}
 
 	// 检查第一个表达式是否被淘汰
-	if _, exists := cache.cache[exprs[0]]; exists {
+	if _, exists := cachedSchedule(p, exprs[0]); exists {
 		t.Errorf("LRU未正常工作: 表达式 %q 应该被淘汰", exprs[0])
 	}
 
 	// 检查最后一个表达式是否被缓存
-	if _, exists := cache.cache[exprs[len(exprs)-1]]; !exists {
+	if _, exists := cachedSchedule(p, exprs[len(exprs)-1]); !exists {
 		t.Errorf("最新的表达式 %q 未被缓存", exprs[len(exprs)-1])
 	}
 }
@@ -117,9 +94,7 @@ func TestCacheLRU(t *testing.T) {
 // TestCacheConcurrency 测试并发安全性
 func TestCacheConcurrency(t *testing.T) {
 	// 清空全局缓存，确保测试环境干净
-	parseCachesLock.Lock()
-	parseCaches = make(map[ParseOption]*parserCache)
-	parseCachesLock.Unlock()
+	resetCaches(t, DefaultCacheSize)
 
 	// 使用标准解析器
 	p := standardParser
@@ -156,12 +131,8 @@ func TestCacheConcurrency(t *testing.T) {
 	wg.Wait()
 
 	// 验证所有表达式都被正确缓存
-	cache := getCacheForParser(p)
-	cache.mu.RLock()
-	defer cache.mu.RUnlock()
-
 	for _, expr := range exprs {
-		if _, exists := cache.cache[expr]; !exists {
+		if _, exists := cachedSchedule(p, expr); !exists {
 			t.Errorf("表达式 %q 未被缓存", expr)
 		}
 	}
@@ -174,9 +145,7 @@ func TestCachePerformance(t *testing.T) {
 	}
 
 	// 清空全局缓存，确保测试环境干净
-	parseCachesLock.Lock()
-	parseCaches = make(map[ParseOption]*parserCache)
-	parseCachesLock.Unlock()
+	resetCaches(t, DefaultCacheSize)
 
 	// 使用标准解析器
 	p := standardParser
@@ -208,3 +177,193 @@ func TestCachePerformance(t *testing.T) {
 	// 这里我们不测试无缓存的情况，因为那需要修改代码
 	// 但在实际开发中，可以临时禁用缓存进行对比测试
 }
+
+// TestCacheSecondChance 测试近期命中过的表达式在淘汰时获得二次机会
+func TestCacheSecondChance(t *testing.T) {
+	resetCaches(t, 3)
+	p := standardParser
+
+	for _, expr := range []string{"0 1 * * *", "0 2 * * *", "0 3 * * *"} {
+		if _, err := p.Parse(expr); err != nil {
+			t.Fatalf("解析表达式失败: %v", err)
+		}
+	}
+	// 命中第一个表达式，使其在下一次淘汰中被跳过
+	if _, err := p.Parse("0 1 * * *"); err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+	if _, err := p.Parse("0 4 * * *"); err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+
+	if _, exists := cachedSchedule(p, "0 1 * * *"); !exists {
+		t.Error("近期命中的表达式不应被淘汰")
+	}
+	if _, exists := cachedSchedule(p, "0 2 * * *"); exists {
+		t.Error("未被访问的最旧表达式应被淘汰")
+	}
+}
+
+// TestCacheStats 测试命中、未命中与淘汰计数
+func TestCacheStats(t *testing.T) {
+	resetCaches(t, 2)
+	p := standardParser
+	before := GetCacheStats()
+
+	for _, expr := range []string{"0 1 * * *", "0 1 * * *", "0 2 * * *", "0 3 * * *", "bad"} {
+		_, _ = p.Parse(expr)
+	}
+
+	stats := GetCacheStats()
+	if got := stats.Hits - before.Hits; got != 1 {
+		t.Errorf("命中次数 = %d, 期望 1", got)
+	}
+	if got := stats.Misses - before.Misses; got != 4 {
+		t.Errorf("未命中次数 = %d, 期望 4", got)
+	}
+	if got := stats.Evictions - before.Evictions; got != 1 {
+		t.Errorf("淘汰次数 = %d, 期望 1", got)
+	}
+	if stats.Entries != 2 || stats.Capacity != 2 {
+		t.Errorf("条目数 / 容量 = %d / %d, 期望 2 / 2", stats.Entries, stats.Capacity)
+	}
+}
+
+// TestCacheDisabled 测试容量为 0 时禁用缓存
+func TestCacheDisabled(t *testing.T) {
+	resetCaches(t, 0)
+	p := standardParser
+
+	first, err := p.Parse("*/5 * * * *")
+	if err != nil {
+		t.Fatalf("解析表达式失败: %v", err)
+	}
+	second, _ := p.Parse("*/5 * * * *")
+	if first == second {
+		t.Error("禁用缓存后不应返回同一个调度对象")
+	}
+	if stats := GetCacheStats(); stats.Entries != 0 || stats.Capacity != 0 {
+		t.Errorf("禁用缓存后条目数 / 容量 = %d / %d", stats.Entries, stats.Capacity)
+	}
+}
+
+// TestCacheShardCount 测试分片数随容量增长且总容量不小于设定值
+func TestCacheShardCount(t *testing.T) {
+	for _, capacity := range []int{1, 5, 64, 1000, 50000} {
+		cache := newParserCache(capacity)
+		total := 0
+		for _, shard := range cache.shards {
+			total += shard.capacity
+		}
+		if total < capacity {
+			t.Errorf("容量 %d 分片后总容量 %d 不足", capacity, total)
+		}
+		if n := len(cache.shards); n&(n-1) != 0 {
+			t.Errorf("容量 %d 的分片数 %d 不是 2 的幂", capacity, n)
+		}
+	}
+	if n := len(newParserCache(DefaultCacheSize).shards); n < 2 {
+		t.Errorf("默认容量应分片, 实际分片数 %d", n)
+	}
+}
+
+// BenchmarkParseCacheParallel 并发命中缓存的性能，对比全局写锁 LRU（旧实现）与分片 CLOCK 缓存
+func BenchmarkParseCacheParallel(b *testing.B) {
+	specs := make([]string, 512)
+	for i := range specs {
+		specs[i] = fmt.Sprintf("%d %d * * *", i%60, i%24)
+	}
+
+	b.Run("global-lock-lru", func(b *testing.B) {
+		cache := newLockedLRU(DefaultCacheSize)
+		for _, spec := range specs {
+			cache.parse(spec)
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				cache.parse(specs[i%len(specs)])
+			}
+		})
+	})
+
+	b.Run("sharded-clock", func(b *testing.B) {
+		resetCaches(b, DefaultCacheSize)
+		for _, spec := range specs {
+			_, _ = standardParser.Parse(spec)
+		}
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				_, _ = standardParser.Parse(specs[i%len(specs)])
+			}
+		})
+	})
+}
+
+// lockedLRU 旧版缓存实现：命中时升级为写锁移动访问链表，仅用于基准对比
+type lockedLRU struct {
+	mu    sync.RWMutex
+	items map[string]*list.Element
+	order *list.List
+	size  int
+}
+
+type lockedLRUItem struct {
+	spec     string
+	schedule Schedule
+}
+
+func newLockedLRU(size int) *lockedLRU {
+	return &lockedLRU{items: make(map[string]*list.Element), order: list.New(), size: size}
+}
+
+func (c *lockedLRU) parse(spec string) Schedule {
+	c.mu.RLock()
+	_, ok := c.items[spec]
+	c.mu.RUnlock()
+	if ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if elem, ok := c.items[spec]; ok {
+			c.order.MoveToBack(elem)
+			return elem.Value.(*lockedLRUItem).schedule
+		}
+	}
+
+	schedule, _ := standardParser.parseNoCache(spec)
+	if !ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Front()
+		delete(c.items, oldest.Value.(*lockedLRUItem).spec)
+		c.order.Remove(oldest)
+	}
+	c.items[spec] = c.order.PushBack(&lockedLRUItem{spec: spec, schedule: schedule})
+	return schedule
+}
+
+// resetCaches 清空全局缓存并设置容量，测试结束后恢复默认容量
+func resetCaches(tb testing.TB, size int) {
+	tb.Helper()
+	SetCacheSize(size)
+	tb.Cleanup(func() { SetCacheSize(DefaultCacheSize) })
+}
+
+// cachedSchedule 读取缓存中的调度结果，不影响访问位与统计
+func cachedSchedule(p Parser, spec string) (Schedule, bool) {
+	value, ok := parseCaches.Load(p.options)
+	if !ok {
+		return nil, false
+	}
+	shard := value.(*parserCache).shard(spec)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	entry, ok := shard.entries[spec]
+	if !ok {
+		return nil, false
+	}
+	return entry.schedule, true
+}
@@ -0,0 +1,65 @@
+package parser
+
+import "time"
+
+// maxBusinessDayOfMonth 单月营业日数量上限（31 天中最多 23 个工作日）
+const maxBusinessDayOfMonth = 23
+
+// HolidayCalendar 节假日日历，用于营业日（BD）语法的计算。
+// date 为当天零点，实现方应只比较年月日。
+type HolidayCalendar interface {
+	IsHoliday(date time.Time) bool
+}
+
+// WithHolidayCalendar 返回绑定了节假日日历的调度副本。
+// 解析结果会被全局缓存共享，因此不能原地修改；未使用营业日语法的调度原样返回。
+func WithHolidayCalendar(schedule Schedule, calendar HolidayCalendar) Schedule {
+	if composite, ok := schedule.(compositeSchedule); ok && calendar != nil {
+		return composite.mapSchedules(func(child Schedule) Schedule {
+			return WithHolidayCalendar(child, calendar)
+		})
+	}
+
+	spec, ok := schedule.(*SpecSchedule)
+	if !ok || calendar == nil || !spec.hasBusinessDaySyntax() {
+		return schedule
+	}
+
+	cloned := *spec
+	cloned.calendar = calendar
+	return &cloned
+}
+
+// hasBusinessDaySyntax 检查是否使用了 BD/BDL 营业日语法
+func (s *SpecSchedule) hasBusinessDaySyntax() bool {
+	return s.lastBusinessDayOfMonth || len(s.businessDaysOfMonth) > 0
+}
+
+// isBusinessDay 判断给定日期是否为营业日：周一至周五且不在节假日日历中
+func isBusinessDay(date time.Time, calendar HolidayCalendar) bool {
+	switch date.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	if calendar != nil && calendar.IsHoliday(date) {
+		return false
+	}
+	return true
+}
+
+// businessDaysInMonth 按顺序返回给定年月的全部营业日
+func businessDaysInMonth(year, month int, loc *time.Location, calendar HolidayCalendar) []int {
+	if loc == nil {
+		loc = time.UTC
+	}
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	days := make([]int, 0, maxBusinessDayOfMonth)
+	for day := 1; day <= lastDay; day++ {
+		if isBusinessDay(time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc), calendar) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
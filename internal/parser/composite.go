@@ -0,0 +1,249 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// compositeSearchYears 组合调度向后查找的最大年限，与 SpecSchedule 保持一致
+const compositeSearchYears = 5
+
+// exceptMaxSkips ExceptSchedule 连续跳过被排除触发点的次数上限（约 45 天的逐分钟触发）。
+// Excluded 覆盖 Schedule 时（如 "* * * * * ! * * * * *"）逐分钟查找 5 年会阻塞约 1 秒，超过上限即视为不再触发
+const exceptMaxSkips = 1 << 16
+
+// compositeSchedule 由多个子调度组合而成的调度。
+// WithLocation / WithHolidayCalendar / WithDSTPolicy 通过 mapSchedules 作用到每个子调度。
+type compositeSchedule interface {
+	Schedule
+	mapSchedules(fn func(Schedule) Schedule) Schedule
+}
+
+// UnionSchedule 并集：任一子调度触发即触发
+type UnionSchedule struct {
+	Schedules []Schedule
+}
+
+// Union 返回多个调度的并集，同一时刻多个子调度同时触发时只触发一次
+func Union(schedules ...Schedule) Schedule {
+	if len(schedules) == 1 {
+		return schedules[0]
+	}
+	return &UnionSchedule{Schedules: append([]Schedule(nil), schedules...)}
+}
+
+// Next 返回各子调度中最早的下一个触发点
+func (u *UnionSchedule) Next(t time.Time) time.Time {
+	var earliest time.Time
+	for _, schedule := range u.Schedules {
+		next := schedule.Next(t)
+		if next.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || next.Before(earliest) {
+			earliest = next
+		}
+	}
+	return earliest
+}
+
+func (u *UnionSchedule) mapSchedules(fn func(Schedule) Schedule) Schedule {
+	mapped := make([]Schedule, len(u.Schedules))
+	for i, schedule := range u.Schedules {
+		mapped[i] = fn(schedule)
+	}
+	return &UnionSchedule{Schedules: mapped}
+}
+
+// ExceptSchedule 差集：Schedule 的触发点中，排除 Excluded 在同一分钟内也会触发的部分
+type ExceptSchedule struct {
+	Schedule Schedule
+	Excluded Schedule
+}
+
+// Except 返回从 schedule 中排除 excluded 后的调度。
+// 以分钟为粒度比较：excluded 在某一分钟内触发，则 schedule 在该分钟内的触发点均被排除。
+func Except(schedule, excluded Schedule) Schedule {
+	return &ExceptSchedule{Schedule: schedule, Excluded: excluded}
+}
+
+// Next 返回未被排除的下一个触发点，连续被排除超过 exceptMaxSkips 次时返回零值
+func (e *ExceptSchedule) Next(t time.Time) time.Time {
+	limit := t.AddDate(compositeSearchYears, 0, 0)
+	cur := t
+	for range exceptMaxSkips {
+		next := e.Schedule.Next(cur)
+		if next.IsZero() || next.After(limit) {
+			return time.Time{}
+		}
+		if !firesInMinute(e.Excluded, next) {
+			return next
+		}
+		// 整分钟被排除，直接跳到下一分钟
+		cur = next.Truncate(time.Minute).Add(time.Minute - time.Nanosecond)
+	}
+	return time.Time{}
+}
+
+func (e *ExceptSchedule) mapSchedules(fn func(Schedule) Schedule) Schedule {
+	return &ExceptSchedule{Schedule: fn(e.Schedule), Excluded: fn(e.Excluded)}
+}
+
+// WithinSchedule 交集：仅保留 Window 在同一分钟内也会触发的 Schedule 触发点
+type WithinSchedule struct {
+	Schedule Schedule
+	Window   Schedule
+}
+
+// Within 返回将 schedule 限定在 window 内的调度。
+// window 以其触发的分钟作为有效时段，例如 "* 9-17 * * 1-5" 表示工作日 9:00-17:59。
+func Within(schedule, window Schedule) Schedule {
+	return &WithinSchedule{Schedule: schedule, Window: window}
+}
+
+// Next 返回落在窗口内的下一个触发点
+func (w *WithinSchedule) Next(t time.Time) time.Time {
+	limit := t.AddDate(compositeSearchYears, 0, 0)
+	cur := t
+	for {
+		next := w.Schedule.Next(cur)
+		if next.IsZero() || next.After(limit) {
+			return time.Time{}
+		}
+
+		minute := next.Truncate(time.Minute)
+		open := w.Window.Next(minute.Add(-time.Nanosecond))
+		if open.IsZero() {
+			return time.Time{}
+		}
+		if open.Before(minute.Add(time.Minute)) {
+			return next
+		}
+		// 跳到窗口下一次打开的分钟
+		cur = open.Truncate(time.Minute).Add(-time.Nanosecond)
+	}
+}
+
+func (w *WithinSchedule) mapSchedules(fn func(Schedule) Schedule) Schedule {
+	return &WithinSchedule{Schedule: fn(w.Schedule), Window: fn(w.Window)}
+}
+
+// firesInMinute 判断 schedule 是否在 t 所在的分钟内触发
+func firesInMinute(schedule Schedule, t time.Time) bool {
+	minute := t.Truncate(time.Minute)
+	next := schedule.Next(minute.Add(-time.Nanosecond))
+	return !next.IsZero() && next.Before(minute.Add(time.Minute))
+}
+
+// IsComposite 判断表达式是否使用了组合语法（| & !）
+func IsComposite(spec string) bool {
+	return strings.ContainsAny(spec, "|&!")
+}
+
+// ParseComposite 解析组合表达式，每个子表达式交由 parse 解析。
+//
+// 语法：
+//   - A | B   并集，A 或 B 触发即触发
+//   - A & W   A 的触发点中仅保留 W 所在分钟内的部分（Within）
+//   - A ! E   A 的触发点中排除 E 所在分钟内的部分（Except）
+//
+// & 与 ! 的优先级高于 |，同级从左到右结合；
+// TZ= / CRON_TZ= 前缀只能写在整个表达式开头，作用于所有子表达式。
+// 子表达式的 *ParseError 偏移会换算为相对整个 spec 的位置。
+func ParseComposite(spec string, parse func(string) (Schedule, error)) (Schedule, error) {
+	full := spec
+	spec = strings.TrimSpace(spec)
+	offset := strings.Index(full, spec)
+
+	var loc *time.Location
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		prefix, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(prefix, "=")
+		var err error
+		loc, err = time.LoadLocation(name)
+		if err != nil {
+			return nil, withSpec(timezoneError(ReasonInvalidTimezone, spec, name, "provided bad location %s: %v", name, err), full, offset)
+		}
+		offset += len(prefix) + 1
+		spec = rest
+	}
+
+	terms := strings.Split(spec, "|")
+	schedules := make([]Schedule, 0, len(terms))
+	for _, term := range terms {
+		schedule, err := parseCompositeTerm(term, offset, parse)
+		if err != nil {
+			return nil, withSpec(err, full, 0)
+		}
+		schedules = append(schedules, schedule)
+		offset += len(term) + 1
+	}
+
+	return WithLocation(Union(schedules...), loc), nil
+}
+
+// parseCompositeTerm 解析由 & / ! 连接的单个并集项，offset 为 term 在整个表达式中的偏移
+func parseCompositeTerm(term string, offset int, parse func(string) (Schedule, error)) (Schedule, error) {
+	var (
+		result Schedule
+		op     byte
+	)
+	for {
+		idx := strings.IndexAny(term, "&!")
+		operand := term
+		if idx >= 0 {
+			operand = term[:idx]
+		}
+
+		schedule, err := parseCompositeOperand(operand, offset, parse)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case 0:
+			result = schedule
+		case '&':
+			result = Within(result, schedule)
+		case '!':
+			result = Except(result, schedule)
+		}
+
+		if idx < 0 {
+			return result, nil
+		}
+		op = term[idx]
+		term = term[idx+1:]
+		offset += idx + 1
+	}
+}
+
+// parseCompositeOperand 解析组合表达式中的单个子表达式，offset 为 operand 在整个表达式中的偏移
+func parseCompositeOperand(operand string, offset int, parse func(string) (Schedule, error)) (Schedule, error) {
+	trimmed := strings.TrimSpace(operand)
+	offset += strings.Index(operand, trimmed)
+	if trimmed == "" {
+		err := newParseError(ReasonEmpty, "", "empty expression in composite spec")
+		err.Offset = offset
+		return nil, err
+	}
+	if strings.HasPrefix(trimmed, "TZ=") || strings.HasPrefix(trimmed, "CRON_TZ=") {
+		prefix, _, _ := strings.Cut(trimmed, " ")
+		return nil, withSpec(timezoneError(ReasonInvalidTimezone, trimmed, prefix,
+			"time zone prefix must precede the whole composite spec: %s", trimmed), "", offset)
+	}
+
+	schedule, err := parse(trimmed)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			return nil, withSpec(parseErr, "", offset)
+		}
+		return nil, fmt.Errorf("%s: %w", trimmed, err)
+	}
+	if _, ok := schedule.(*StartupSchedule); ok {
+		return nil, withSpec(descriptorError(fmt.Errorf("startup descriptor cannot be combined: %s", trimmed), trimmed), "", offset)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseTestSpec(spec string) (Schedule, error) {
+	if len(strings.Fields(spec)) == 5 {
+		return ParseStandard(spec)
+	}
+	return MustNewParser(Second | Minute | Hour | Dom | Month | Dow | Descriptor).Parse(spec)
+}
+
+// TestCompositeSchedules 测试并集、交集与差集组合调度
+func TestCompositeSchedules(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.UTC)
+		if err != nil {
+			t.Fatalf("invalid time %q: %v", value, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from string
+		want []string
+	}{
+		{
+			name: "工作时间每15分钟加23点",
+			spec: "*/15 * * * * & * 9-17 * * 1-5 | 0 23 * * *",
+			from: "2025-06-06 17:40:00", // 周五
+			want: []string{"2025-06-06 17:45:00", "2025-06-06 23:00:00", "2025-06-07 23:00:00", "2025-06-08 23:00:00", "2025-06-09 09:00:00"},
+		},
+		{
+			name: "排除午休",
+			spec: "0 * * * * ! * 12 * * *",
+			from: "2025-06-06 10:30:00",
+			want: []string{"2025-06-06 11:00:00", "2025-06-06 13:00:00"},
+		},
+		{
+			name: "交集后再排除",
+			spec: "*/30 * * * * & * 9-10 * * * ! 30 9 * * *",
+			from: "2025-06-06 08:00:00",
+			want: []string{"2025-06-06 09:00:00", "2025-06-06 10:00:00", "2025-06-06 10:30:00", "2025-06-07 09:00:00"},
+		},
+		{
+			name: "并集去重",
+			spec: "0 */2 * * * | 0 */3 * * *",
+			from: "2025-06-06 00:00:00",
+			want: []string{"2025-06-06 02:00:00", "2025-06-06 03:00:00", "2025-06-06 04:00:00", "2025-06-06 06:00:00", "2025-06-06 08:00:00"},
+		},
+		{
+			name: "6段秒级窗口",
+			spec: "*/20 * * * * * & * 0 9 * * *",
+			from: "2025-06-06 08:59:50",
+			want: []string{"2025-06-06 09:00:00", "2025-06-06 09:00:20", "2025-06-06 09:00:40", "2025-06-07 09:00:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseComposite(tt.spec, parseTestSpec)
+			if err != nil {
+				t.Fatalf("ParseComposite(%q) failed: %v", tt.spec, err)
+			}
+			next := at(tt.from)
+			for i, want := range tt.want {
+				next = schedule.Next(next)
+				if !next.Equal(at(want)) {
+					t.Fatalf("第 %d 次触发 = %v; want %s", i+1, next, want)
+				}
+			}
+		})
+	}
+}
+
+// TestCompositeNeverFires 测试窗口永不打开时返回零值
+func TestCompositeNeverFires(t *testing.T) {
+	schedule, err := ParseComposite("0 * * * * & 0 0 30 2 *", parseTestSpec)
+	if err != nil {
+		t.Fatalf("ParseComposite failed: %v", err)
+	}
+	if next := schedule.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); !next.IsZero() {
+		t.Fatalf("expected zero time, got %v", next)
+	}
+}
+
+// TestCompositeExceptAll 测试排除调度覆盖基础调度时及时返回零值，不逐分钟查找 5 年
+func TestCompositeExceptAll(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, spec := range []string{"* * * * * ! * * * * *", "* * * * * * ! * * * * *"} {
+		schedule, err := ParseComposite(spec, parseTestSpec)
+		if err != nil {
+			t.Fatalf("ParseComposite(%q) failed: %v", spec, err)
+		}
+		began := time.Now()
+		if next := schedule.Next(from); !next.IsZero() {
+			t.Fatalf("%q: expected zero time, got %v", spec, next)
+		}
+		if elapsed := time.Since(began); elapsed > 500*time.Millisecond {
+			t.Fatalf("%q: Next took %v", spec, elapsed)
+		}
+	}
+
+	// 长时间的排除仍在上限内：工作日全部排除，下一次为周六 00:00
+	schedule, err := ParseComposite("* * * * * ! * * * * 1-5", parseTestSpec)
+	if err != nil {
+		t.Fatalf("ParseComposite failed: %v", err)
+	}
+	want := time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)
+	if next := schedule.Next(from); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+// TestCompositeLocation 测试前缀时区作用于所有子表达式
+func TestCompositeLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone unavailable: %v", err)
+	}
+
+	schedule, err := ParseComposite("TZ=Asia/Tokyo 0 9 * * * | 0 18 * * *", parseTestSpec)
+	if err != nil {
+		t.Fatalf("ParseComposite failed: %v", err)
+	}
+	loc, explicit := ExplicitLocation(schedule)
+	if !explicit || loc.String() != "Asia/Tokyo" {
+		t.Fatalf("ExplicitLocation = %v, %v; want Asia/Tokyo", loc, explicit)
+	}
+
+	next := schedule.Next(time.Date(2025, 6, 6, 10, 0, 0, 0, tokyo))
+	if local := next.In(tokyo); local.Hour() != 18 {
+		t.Fatalf("expected 18:00 Asia/Tokyo, got %v", local)
+	}
+}
+
+// TestCompositeErrors 测试组合表达式的错误输入
+func TestCompositeErrors(t *testing.T) {
+	for _, spec := range []string{
+		"0 9 * * * |",
+		"| 0 9 * * *",
+		"0 9 * * * & ",
+		"0 9 * * * | TZ=UTC 0 18 * * *",
+		"TZ=Invalid/Zone 0 9 * * * | 0 18 * * *",
+		"0 9 * * * | 99 * * * *",
+	} {
+		if _, err := ParseComposite(spec, parseTestSpec); err == nil {
+			t.Errorf("ParseComposite(%q) expected error", spec)
+		}
+	}
+}
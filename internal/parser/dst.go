@@ -0,0 +1,100 @@
+package parser
+
+import "time"
+
+// DSTPolicy 夏令时切换处理策略，重叠（回拨）与空隙（拨快）两类策略可按位组合。
+// 零值沿用默认行为：重叠时段的挂钟时间执行两次，落在空隙中的挂钟时间被跳过。
+type DSTPolicy uint8
+
+const (
+	DSTRunBoth          DSTPolicy = 1 << iota // 重叠时段两次均执行（默认）
+	DSTRunOnceInOverlap                       // 重叠时段仅执行第一次
+	DSTSkipGap                                // 跳过落在空隙中的触发点（默认）
+	DSTShiftGapForward                        // 空隙中的触发点按空隙长度顺延（如 02:30 -> 03:30）
+)
+
+// WithDSTPolicy 返回应用了夏令时策略的调度副本。
+// 与 WithHolidayCalendar 一样不修改缓存中的共享解析结果；组合调度作用到每个子调度，其余非 SpecSchedule 原样返回。
+func WithDSTPolicy(schedule Schedule, policy DSTPolicy) Schedule {
+	if composite, ok := schedule.(compositeSchedule); ok && policy != 0 {
+		return composite.mapSchedules(func(child Schedule) Schedule {
+			return WithDSTPolicy(child, policy)
+		})
+	}
+
+	spec, ok := schedule.(*SpecSchedule)
+	if !ok || policy == 0 {
+		return schedule
+	}
+
+	cloned := *spec
+	cloned.dstPolicy = policy
+	return &cloned
+}
+
+// applyDSTPolicy 根据策略修正默认算法给出的下一个触发点
+func (s *SpecSchedule) applyDSTPolicy(from, next time.Time, loc *time.Location) time.Time {
+	for {
+		if s.dstPolicy&DSTShiftGapForward != 0 {
+			if shifted := s.nextInGap(from, next, loc); !shifted.IsZero() {
+				next = shifted
+			}
+		}
+		if next.IsZero() || s.dstPolicy&DSTRunOnceInOverlap == 0 || !isRepeatedWallClock(next, loc) {
+			return next
+		}
+		// 第二次出现的挂钟时间，继续向后查找
+		from = next
+		next = s.nextIn(from, loc)
+	}
+}
+
+// nextInGap 查找 (from, next) 之间因时钟拨快被跳过的匹配点，返回其顺延后的时间；没有则返回零值。
+// 空隙内的挂钟时间按切换前的偏移换算，恰好等价于按空隙长度顺延。
+func (s *SpecSchedule) nextInGap(from, next time.Time, loc *time.Location) time.Time {
+	limit := next
+	if limit.IsZero() {
+		limit = from.AddDate(5, 0, 0)
+	}
+
+	cur := from.In(loc)
+	for {
+		_, end := cur.ZoneBounds()
+		if end.IsZero() || end.After(limit) {
+			return time.Time{}
+		}
+
+		_, before := cur.Zone()
+		_, after := end.In(loc).Zone()
+		if after > before {
+			gap := time.Duration(after-before) * time.Second
+			fixed := time.FixedZone(cur.Location().String(), before)
+			start := end.Add(-time.Second)
+			if start.Before(from) {
+				start = from
+			}
+			candidate := s.nextIn(start.In(fixed), fixed)
+			if !candidate.IsZero() && !candidate.Before(end) && candidate.Before(end.Add(gap)) &&
+				(next.IsZero() || candidate.Before(next)) {
+				return candidate.In(loc)
+			}
+		}
+		cur = end.In(loc)
+	}
+}
+
+// isRepeatedWallClock 判断 t 是否为时钟回拨后第二次出现的挂钟时间
+func isRepeatedWallClock(t time.Time, loc *time.Location) bool {
+	t = t.In(loc)
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, prevOffset := start.Add(-time.Second).In(loc).Zone()
+	if prevOffset <= offset {
+		return false
+	}
+	return t.Sub(start) < time.Duration(prevOffset-offset)*time.Second
+}
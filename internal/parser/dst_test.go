@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDSTPolicy 测试不同时区下夏令时空隙与重叠时段的处理策略
+func TestDSTPolicy(t *testing.T) {
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", value)
+		if err != nil {
+			t.Fatalf("invalid time %q: %v", value, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name   string
+		zone   string
+		spec   string
+		day    string // 切换当天（本地日期）
+		policy DSTPolicy
+		want   []string // 连续两次触发点（UTC）
+	}{
+		// 空隙：时钟拨快导致挂钟时间不存在
+		{"Berlin 空隙默认跳过", "Europe/Berlin", "30 2 * * *", "2025-03-30", 0, []string{"2025-03-31 00:30", "2025-04-01 00:30"}},
+		{"Berlin 空隙显式跳过", "Europe/Berlin", "30 2 * * *", "2025-03-30", DSTSkipGap, []string{"2025-03-31 00:30", "2025-04-01 00:30"}},
+		{"Berlin 空隙顺延", "Europe/Berlin", "30 2 * * *", "2025-03-30", DSTShiftGapForward, []string{"2025-03-30 01:30", "2025-03-31 00:30"}},
+		{"New York 空隙顺延", "America/New_York", "30 2 * * *", "2025-03-09", DSTShiftGapForward, []string{"2025-03-09 07:30", "2025-03-10 06:30"}},
+		{"Sydney 空隙顺延", "Australia/Sydney", "30 2 * * *", "2025-10-05", DSTShiftGapForward, []string{"2025-10-04 16:30", "2025-10-05 15:30"}},
+		{"Lord Howe 半小时空隙顺延", "Australia/Lord_Howe", "15 2 * * *", "2025-10-05", DSTShiftGapForward, []string{"2025-10-04 15:45", "2025-10-05 15:15"}},
+
+		// 重叠：时钟回拨导致挂钟时间出现两次
+		{"Berlin 重叠默认两次", "Europe/Berlin", "30 2 * * *", "2025-10-26", 0, []string{"2025-10-26 00:30", "2025-10-26 01:30"}},
+		{"Berlin 重叠显式两次", "Europe/Berlin", "30 2 * * *", "2025-10-26", DSTRunBoth, []string{"2025-10-26 00:30", "2025-10-26 01:30"}},
+		{"Berlin 重叠仅一次", "Europe/Berlin", "30 2 * * *", "2025-10-26", DSTRunOnceInOverlap, []string{"2025-10-26 00:30", "2025-10-27 01:30"}},
+		{"New York 重叠仅一次", "America/New_York", "30 1 * * *", "2025-11-02", DSTRunOnceInOverlap, []string{"2025-11-02 05:30", "2025-11-03 06:30"}},
+		{"Sydney 重叠仅一次", "Australia/Sydney", "30 2 * * *", "2025-04-06", DSTRunOnceInOverlap, []string{"2025-04-05 15:30", "2025-04-06 16:30"}},
+		{"Lord Howe 半小时重叠仅一次", "Australia/Lord_Howe", "45 1 * * *", "2025-04-06", DSTRunOnceInOverlap, []string{"2025-04-05 14:45", "2025-04-06 15:15"}},
+
+		// 组合策略：同一任务同时处理空隙与重叠
+		{"Berlin 组合策略-空隙", "Europe/Berlin", "30 2 * * *", "2025-03-30", DSTRunOnceInOverlap | DSTShiftGapForward, []string{"2025-03-30 01:30", "2025-03-31 00:30"}},
+		{"Berlin 组合策略-重叠", "Europe/Berlin", "30 2 * * *", "2025-10-26", DSTRunOnceInOverlap | DSTShiftGapForward, []string{"2025-10-26 00:30", "2025-10-27 01:30"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Skipf("time zone %s unavailable: %v", tt.zone, err)
+			}
+			day, err := time.ParseInLocation("2006-01-02", tt.day, loc)
+			if err != nil {
+				t.Fatalf("invalid day: %v", err)
+			}
+
+			sched, err := ParseStandard("TZ=" + tt.zone + " " + tt.spec)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			sched = WithDSTPolicy(sched, tt.policy)
+
+			from := day
+			for i, want := range tt.want {
+				next := sched.Next(from)
+				if !next.Equal(utc(want)) {
+					t.Fatalf("run %d: expected %s UTC, got %v (%v UTC)", i+1, want, next, next.UTC())
+				}
+				from = next
+			}
+		})
+	}
+}
+
+// TestDSTShiftGapForwardEveryHalfHour 测试高频任务跨越空隙时的连续触发点
+func TestDSTShiftGapForwardEveryHalfHour(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone unavailable: %v", err)
+	}
+	sched, err := ParseStandard("TZ=Europe/Berlin */30 * * * *")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	sched = WithDSTPolicy(sched, DSTShiftGapForward)
+
+	// 01:30 CET 之后依次为 03:00、03:30 CEST，顺延的 02:00/02:30 与之重合，不会多出触发点
+	from := time.Date(2025, 3, 30, 1, 30, 0, 0, loc)
+	want := []time.Time{
+		time.Date(2025, 3, 30, 3, 0, 0, 0, loc),
+		time.Date(2025, 3, 30, 3, 30, 0, 0, loc),
+		time.Date(2025, 3, 30, 4, 0, 0, 0, loc),
+	}
+	for i, expected := range want {
+		next := sched.Next(from)
+		if !next.Equal(expected) {
+			t.Fatalf("run %d: expected %v, got %v", i+1, expected, next)
+		}
+		from = next
+	}
+}
+
+// TestWithDSTPolicyDoesNotMutateCache 测试策略绑定返回副本
+func TestWithDSTPolicyDoesNotMutateCache(t *testing.T) {
+	sched, err := ParseStandard("30 2 * * *")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if WithDSTPolicy(sched, 0) != sched {
+		t.Fatal("zero policy should return the schedule as is")
+	}
+	bound := WithDSTPolicy(sched, DSTRunOnceInOverlap)
+	if bound == sched || sched.(*SpecSchedule).dstPolicy != 0 {
+		t.Fatal("expected a copy without mutating the cached schedule")
+	}
+
+	every, err := ParseStandard("@every 1h")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if WithDSTPolicy(every, DSTRunOnceInOverlap) != every {
+		t.Fatal("constant delay schedule should be returned as is")
+	}
+}
@@ -1,9 +1,125 @@
 package parser
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // 预定义的错误类型，便于用户处理特定错误情况
 var (
 	// 公开错误
 	ErrUnsupportedSpec = fmt.Errorf("无效的cron表达式格式")
 )
+
+// ParseErrorReason 解析错误的原因代码，供程序判断错误类别
+type ParseErrorReason string
+
+const (
+	ReasonEmpty              ParseErrorReason = "empty"               // 表达式或子表达式为空
+	ReasonFieldCount         ParseErrorReason = "field_count"         // 字段数量不符合解析选项
+	ReasonInvalidNumber      ParseErrorReason = "invalid_number"      // 数字或名称无法解析
+	ReasonOutOfRange         ParseErrorReason = "out_of_range"        // 数值超出字段取值范围
+	ReasonInvalidRange       ParseErrorReason = "invalid_range"       // 范围写法错误，如起点大于终点
+	ReasonInvalidStep        ParseErrorReason = "invalid_step"        // 步长写法错误
+	ReasonInvalidSyntax      ParseErrorReason = "invalid_syntax"      // L/W/#/BD 等特殊语法错误
+	ReasonUnknownDescriptor  ParseErrorReason = "unknown_descriptor"  // 未知的描述符
+	ReasonInvalidDescriptor  ParseErrorReason = "invalid_descriptor"  // 描述符参数错误
+	ReasonDescriptorDisabled ParseErrorReason = "descriptor_disabled" // 解析选项未启用描述符
+	ReasonInvalidTimezone    ParseErrorReason = "invalid_timezone"    // 时区前缀错误
+)
+
+// 字段名称，与 places 顺序一致
+var fieldNames = []string{"second", "minute", "hour", "day-of-month", "month", "day-of-week"}
+
+// ParseError 表达式解析错误，携带出错字段与位置信息，便于界面高亮。
+// Field 为字段名（second/minute/hour/day-of-month/month/day-of-week，描述符与时区错误为 descriptor/timezone），
+// Index 为字段在用户输入中的序号（从 0 开始，-1 表示不针对单个字段），
+// Offset 为 Token 在 Spec 中的字节偏移（-1 表示未知）。
+type ParseError struct {
+	Spec   string
+	Field  string
+	Index  int
+	Token  string
+	Offset int
+	Reason ParseErrorReason
+	Err    error
+}
+
+// Error 实现 error 接口
+func (e *ParseError) Error() string {
+	if e.Index >= 0 {
+		return fmt.Sprintf("failed to parse %s field: %s", e.Field, e.Err)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap 返回底层错误
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError 创建不含字段信息的解析错误，由上层补充字段与位置
+func newParseError(reason ParseErrorReason, token string, format string, args ...any) *ParseError {
+	return &ParseError{
+		Index:  -1,
+		Token:  token,
+		Offset: -1,
+		Reason: reason,
+		Err:    fmt.Errorf(format, args...),
+	}
+}
+
+// fieldError 为字段解析错误补充字段名、序号与偏移
+func fieldError(err error, place, index, fieldOffset int, fieldSpec string) *ParseError {
+	var inner *ParseError
+	if !errors.As(err, &inner) {
+		inner = newParseError(ReasonInvalidSyntax, fieldSpec, "%s", err)
+	}
+
+	result := *inner
+	result.Field = fieldNames[place]
+	result.Index = index
+	if result.Token == "" {
+		result.Token = fieldSpec
+	}
+	result.Offset = fieldOffset
+	if pos := strings.Index(fieldSpec, result.Token); pos >= 0 && fieldOffset >= 0 {
+		result.Offset += pos
+	}
+	return &result
+}
+
+// descriptorError 将描述符解析错误统一标记为 descriptor 字段，Token 为整个描述符
+func descriptorError(err error, spec string) *ParseError {
+	reason := ReasonInvalidDescriptor
+	var inner *ParseError
+	if errors.As(err, &inner) && (inner.Reason == ReasonUnknownDescriptor || inner.Reason == ReasonDescriptorDisabled) {
+		reason = inner.Reason
+		err = inner.Err
+	}
+	return &ParseError{Field: "descriptor", Index: -1, Token: spec, Offset: 0, Reason: reason, Err: err}
+}
+
+// timezoneError 创建时区前缀错误，偏移为 token 在 text 中的位置
+func timezoneError(reason ParseErrorReason, text, token string, format string, args ...any) *ParseError {
+	err := newParseError(reason, token, format, args...)
+	err.Field = "timezone"
+	err.Offset = strings.Index(text, token)
+	return err
+}
+
+// withSpec 将解析错误的偏移平移 base 并记录完整表达式，非 ParseError 原样返回
+func withSpec(err error, spec string, base int) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+
+	result := *parseErr
+	result.Spec = spec
+	if result.Offset >= 0 {
+		result.Offset += base
+	}
+	return &result
+}
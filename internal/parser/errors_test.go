@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseErrorPosition 测试解析错误携带字段、序号、片段与偏移信息
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		field  string
+		index  int
+		token  string
+		offset int
+		reason ParseErrorReason
+	}{
+		{"5段超出范围", "0 61 * * *", "hour", 1, "61", 2, ReasonOutOfRange},
+		{"5段范围颠倒", "0 9 * * 5-1", "day-of-week", 4, "5-1", 8, ReasonInvalidRange},
+		{"5段步长为0", "*/0 * * * *", "minute", 0, "*/0", 0, ReasonInvalidStep},
+		{"5段非法数字", "0 9 * foo *", "month", 3, "foo", 6, ReasonInvalidNumber},
+		{"字段数量错误", "* *", "", -1, "", -1, ReasonFieldCount},
+		{"时区前缀偏移", "TZ=UTC 0 61 * * *", "hour", 1, "61", 9, ReasonOutOfRange},
+		{"非法时区", "TZ=Nope/X 0 * * * *", "timezone", -1, "Nope/X", 3, ReasonInvalidTimezone},
+		{"未知描述符", "@bogus", "descriptor", -1, "@bogus", 0, ReasonUnknownDescriptor},
+		{"描述符参数错误", "@every x", "descriptor", -1, "@every x", 0, ReasonInvalidDescriptor},
+		{"组合表达式子项", "0 * * * * | 0 61 * * *", "hour", 1, "61", 14, ReasonOutOfRange},
+		{"组合表达式空子项", "0 * * * * | ", "", -1, "", 11, ReasonEmpty},
+		{"组合启动描述符", "0 * * * * | @reboot", "descriptor", -1, "@reboot", 12, ReasonInvalidDescriptor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if IsComposite(tt.spec) {
+				_, err = ParseComposite(tt.spec, ParseStandard)
+			} else {
+				_, err = ParseStandard(tt.spec)
+			}
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected *ParseError, got %v", err)
+			}
+			if parseErr.Spec != tt.spec || parseErr.Field != tt.field || parseErr.Index != tt.index ||
+				parseErr.Token != tt.token || parseErr.Offset != tt.offset || parseErr.Reason != tt.reason {
+				t.Fatalf("unexpected error detail: %+v", *parseErr)
+			}
+			if tt.offset >= 0 && tt.token != "" && tt.spec[tt.offset:tt.offset+len(tt.token)] != tt.token {
+				t.Fatalf("offset %d does not point at token %q in %q", tt.offset, tt.token, tt.spec)
+			}
+		})
+	}
+}
+
+// TestParseErrorSecondField 测试6段表达式的字段序号包含秒字段
+func TestParseErrorSecondField(t *testing.T) {
+	p := MustNewParser(Second | Minute | Hour | Dom | Month | Dow)
+	_, err := p.Parse("0 0 61 * * *")
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if parseErr.Field != "hour" || parseErr.Index != 2 || parseErr.Offset != 4 {
+		t.Fatalf("unexpected error detail: %+v", *parseErr)
+	}
+	if got := err.Error(); got != "failed to parse hour field: end of range (61) above maximum (23): 61" {
+		t.Fatalf("unexpected message: %s", got)
+	}
+}
@@ -0,0 +1,66 @@
+package parser
+
+import "time"
+
+// WithLocation 返回显式绑定到指定时区的调度副本，效果等同于 TZ= 前缀。
+// 不修改缓存中的共享解析结果；loc 为 nil 或调度类型不支持时原样返回。
+func WithLocation(schedule Schedule, loc *time.Location) Schedule {
+	if loc == nil {
+		return schedule
+	}
+
+	switch typed := schedule.(type) {
+	case compositeSchedule:
+		return typed.mapSchedules(func(child Schedule) Schedule {
+			return WithLocation(child, loc)
+		})
+	case *SpecSchedule:
+		cloned := *typed
+		cloned.Location = loc
+		cloned.locationSet = true
+		return &cloned
+	case *ConstantDelaySchedule:
+		cloned := *typed
+		cloned.Location = loc
+		cloned.locationSet = true
+		return &cloned
+	case *SolarSchedule:
+		cloned := *typed
+		cloned.Location = loc
+		cloned.locationSet = true
+		return &cloned
+	}
+	return schedule
+}
+
+// ExplicitLocation 返回调度显式指定的时区（TZ= 前缀或 WithLocation），未指定时返回 false。
+// 组合调度返回第一个显式指定了时区的子调度的时区。
+func ExplicitLocation(schedule Schedule) (*time.Location, bool) {
+	switch typed := schedule.(type) {
+	case compositeSchedule:
+		var (
+			loc      *time.Location
+			explicit bool
+		)
+		typed.mapSchedules(func(child Schedule) Schedule {
+			if !explicit {
+				loc, explicit = ExplicitLocation(child)
+			}
+			return child
+		})
+		return loc, explicit
+	case *SpecSchedule:
+		if typed.locationSet && typed.Location != nil {
+			return typed.Location, true
+		}
+	case *ConstantDelaySchedule:
+		if typed.locationSet && typed.Location != nil {
+			return typed.Location, true
+		}
+	case *SolarSchedule:
+		if typed.locationSet && typed.Location != nil {
+			return typed.Location, true
+		}
+	}
+	return nil, false
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Schedule describes a job's duty cycle.
@@ -141,9 +142,9 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 	// Validate number of fields
 	if count := len(fields); count < min || count > max {
 		if min == max {
-			return nil, fmt.Errorf("expected exactly %d fields, found %d: %s", min, count, fields)
+			return nil, newParseError(ReasonFieldCount, "", "expected exactly %d fields, found %d: %s", min, count, fields)
 		}
-		return nil, fmt.Errorf("expected %d to %d fields, found %d: %s", min, max, count, fields)
+		return nil, newParseError(ReasonFieldCount, "", "expected %d to %d fields, found %d: %s", min, max, count, fields)
 	}
 
 	// Populate the optional field if not provided
@@ -171,6 +172,56 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 	return expandedFields, nil
 }
 
+// fieldPositions 返回 places 中每个字段在用户输入中的序号，缺省填充的字段为 -1
+func fieldPositions(count int, options ParseOption) []int {
+	full := options
+	if options&SecondOptional > 0 {
+		full |= Second
+	}
+	if options&DowOptional > 0 {
+		full |= Dow
+	}
+	max := 0
+	for _, place := range places {
+		if full&place > 0 {
+			max++
+		}
+	}
+
+	positions := make([]int, len(places))
+	n := 0
+	for i, place := range places {
+		positions[i] = -1
+		if full&place == 0 {
+			continue
+		}
+		// 可选字段未提供
+		if count < max && ((place == Second && options&SecondOptional > 0) || (place == Dow && options&DowOptional > 0)) {
+			continue
+		}
+		positions[i] = n
+		n++
+	}
+	return positions
+}
+
+// fieldOffsets 返回以空白分隔的各字段在 spec 中的起始字节偏移
+func fieldOffsets(spec string) []int {
+	var offsets []int
+	inField := false
+	for i, r := range spec {
+		if unicode.IsSpace(r) {
+			inField = false
+			continue
+		}
+		if !inField {
+			offsets = append(offsets, i)
+			inField = true
+		}
+	}
+	return offsets
+}
+
 var standardParser = MustNewParser(
 	Minute | Hour | Dom | Month | Dow | Descriptor,
 )
@@ -209,6 +260,8 @@ type specialFieldInfo struct {
 	lastDayOfMonth         bool
 	lastWorkdayOfMonth     bool
 	workdaysOfMonth        map[int]bool
+	businessDaysOfMonth    map[int]bool
+	lastBusinessDayOfMonth bool
 	lastWeekDaysOfWeek     map[int]bool
 	specificWeekDaysOfWeek map[int]bool
 	isRestricted           bool // 是否受限（不是 *）
@@ -247,7 +300,7 @@ func getRange(expr string, r bounds) (uint64, error) {
 				return 0, err
 			}
 		default:
-			return 0, fmt.Errorf("too many hyphens: %s", expr)
+			return 0, newParseError(ReasonInvalidRange, expr, "too many hyphens: %s", expr)
 		}
 	}
 
@@ -268,11 +321,11 @@ func getRange(expr string, r bounds) (uint64, error) {
 			extra = 0
 		}
 	default:
-		return 0, fmt.Errorf("too many slashes: %s", expr)
+		return 0, newParseError(ReasonInvalidStep, expr, "too many slashes: %s", expr)
 	}
 
 	if start < r.min {
-		return 0, fmt.Errorf("beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
+		return 0, newParseError(ReasonOutOfRange, expr, "beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
 	}
 
 	// 特殊处理：周日可以用7表示，但内部仍使用0
@@ -284,14 +337,14 @@ func getRange(expr string, r bounds) (uint64, error) {
 		if r.max == dow.max && end == 7 {
 			end = 0
 		} else {
-			return 0, fmt.Errorf("end of range (%d) above maximum (%d): %s", end, r.max, expr)
+			return 0, newParseError(ReasonOutOfRange, expr, "end of range (%d) above maximum (%d): %s", end, r.max, expr)
 		}
 	}
 	if start > end {
-		return 0, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+		return 0, newParseError(ReasonInvalidRange, expr, "beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
 	}
 	if step == 0 {
-		return 0, fmt.Errorf("step of range should be a positive number: %s", expr)
+		return 0, newParseError(ReasonInvalidStep, expr, "step of range should be a positive number: %s", expr)
 	}
 
 	return getBits(start, end, step) | extra, nil
@@ -319,10 +372,10 @@ func parseIntOrName(expr string, names map[string]uint) (uint, error) {
 func mustParseInt(expr string) (uint, error) {
 	num, err := strconv.Atoi(expr)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse int from %s: %s", expr, err)
+		return 0, newParseError(ReasonInvalidNumber, expr, "failed to parse int from %s: %s", expr, err)
 	}
 	if num < 0 {
-		return 0, fmt.Errorf("negative number (%d) not allowed: %s", num, expr)
+		return 0, newParseError(ReasonInvalidNumber, expr, "negative number (%d) not allowed: %s", num, expr)
 	}
 
 	return uint(num), nil
@@ -349,7 +402,7 @@ func all(r bounds) uint64 {
 	return getBits(r.min, r.max, 1) | starBit
 }
 
-// getDomFieldSpecial 解析 Dom 字段的特殊语法（L/W/LW）
+// getDomFieldSpecial 解析 Dom 字段的特殊语法（L/W/LW/BD）
 func getDomFieldSpecial(field string, r bounds) (*specialFieldInfo, error) {
 	info := &specialFieldInfo{
 		bits:            0,
@@ -381,15 +434,35 @@ func getDomFieldSpecial(field string, r bounds) (*specialFieldInfo, error) {
 			continue
 		}
 
+		// BDL - 每月最后一个营业日；BD3 - 每月第3个营业日
+		if before, ok := strings.CutPrefix(exprLower, "bd"); ok {
+			if before == "l" {
+				info.lastBusinessDayOfMonth = true
+				continue
+			}
+			n, err := mustParseInt(before)
+			if err != nil {
+				return nil, newParseError(ReasonInvalidSyntax, expr, "invalid business day syntax '%s': %s", expr, err)
+			}
+			if n < 1 || n > maxBusinessDayOfMonth {
+				return nil, newParseError(ReasonOutOfRange, expr, "business day %d out of range [1-%d]", n, maxBusinessDayOfMonth)
+			}
+			if info.businessDaysOfMonth == nil {
+				info.businessDaysOfMonth = make(map[int]bool)
+			}
+			info.businessDaysOfMonth[int(n)] = true
+			continue
+		}
+
 		// 15W - 第15天最近的工作日
 		if before, ok := strings.CutSuffix(exprLower, "w"); ok {
 			dayStr := before
 			day, err := mustParseInt(dayStr)
 			if err != nil {
-				return nil, fmt.Errorf("invalid workday syntax '%s': %s", expr, err)
+				return nil, newParseError(ReasonInvalidSyntax, expr, "invalid workday syntax '%s': %s", expr, err)
 			}
 			if day < r.min || day > r.max {
-				return nil, fmt.Errorf("workday %d out of range [%d-%d]", day, r.min, r.max)
+				return nil, newParseError(ReasonOutOfRange, expr, "workday %d out of range [%d-%d]", day, r.min, r.max)
 			}
 			info.workdaysOfMonth[int(day)] = true
 			continue
@@ -432,10 +505,10 @@ func getDowFieldSpecial(field string, r bounds) (*specialFieldInfo, error) {
 			dowStr := before
 			dow, err := parseIntOrName(dowStr, r.names)
 			if err != nil {
-				return nil, fmt.Errorf("invalid last weekday syntax '%s': %s", expr, err)
+				return nil, newParseError(ReasonInvalidSyntax, expr, "invalid last weekday syntax '%s': %s", expr, err)
 			}
 			if dow < r.min || dow > r.max {
-				return nil, fmt.Errorf("day-of-week %d out of range [%d-%d]", dow, r.min, r.max)
+				return nil, newParseError(ReasonOutOfRange, expr, "day-of-week %d out of range [%d-%d]", dow, r.min, r.max)
 			}
 			info.lastWeekDaysOfWeek[int(dow)] = true
 			continue
@@ -445,21 +518,21 @@ func getDowFieldSpecial(field string, r bounds) (*specialFieldInfo, error) {
 		if strings.Contains(exprLower, "#") {
 			parts := strings.Split(exprLower, "#")
 			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid specific weekday syntax '%s'", expr)
+				return nil, newParseError(ReasonInvalidSyntax, expr, "invalid specific weekday syntax '%s'", expr)
 			}
 			dow, err := parseIntOrName(parts[0], r.names)
 			if err != nil {
-				return nil, fmt.Errorf("invalid specific weekday syntax '%s': %s", expr, err)
+				return nil, newParseError(ReasonInvalidSyntax, expr, "invalid specific weekday syntax '%s': %s", expr, err)
 			}
 			week, err := mustParseInt(parts[1])
 			if err != nil {
-				return nil, fmt.Errorf("invalid week number in '%s': %s", expr, err)
+				return nil, newParseError(ReasonInvalidSyntax, expr, "invalid week number in '%s': %s", expr, err)
 			}
 			if dow < r.min || dow > r.max {
-				return nil, fmt.Errorf("day-of-week %d out of range [%d-%d]", dow, r.min, r.max)
+				return nil, newParseError(ReasonOutOfRange, expr, "day-of-week %d out of range [%d-%d]", dow, r.min, r.max)
 			}
 			if week < 1 || week > 5 {
-				return nil, fmt.Errorf("week number %d out of range [1-5]", week)
+				return nil, newParseError(ReasonOutOfRange, expr, "week number %d out of range [1-5]", week)
 			}
 			// 编码为 (week-1)*7 + dow，参考 supercronic 的实现
 			info.specificWeekDaysOfWeek[int((week-1)*7+dow%7)] = true
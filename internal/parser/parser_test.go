@@ -715,3 +715,141 @@ func TestSpecialSyntaxNext(t *testing.T) {
 		})
 	}
 }
+
+type testHolidays map[string]bool
+
+func (h testHolidays) IsHoliday(date time.Time) bool {
+	return h[date.Format("2006-01-02")]
+}
+
+// TestBusinessDaySyntax 测试 BD/BDL 营业日语法与 @businessday 描述符
+func TestBusinessDaySyntax(t *testing.T) {
+	holidays := testHolidays{"2025-03-04": true, "2025-03-31": true, "2025-05-01": true, "2025-05-02": true}
+
+	tests := []struct {
+		name     string
+		expr     string
+		calendar HolidayCalendar
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name: "BD3 - 2025年3月第3个营业日",
+			expr: "0 0 BD3 * *",
+			from: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			// 3月1日是周六，营业日依次为 3、4、5 日
+			expected: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "BD3 - 节假日顺延",
+			expr:     "0 0 BD3 * *",
+			calendar: holidays,
+			from:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "BDL - 2025年3月最后一个营业日",
+			expr:     "0 0 BDL * *",
+			from:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "BDL - 月末节假日前移",
+			expr:     "0 0 BDL * *",
+			calendar: holidays,
+			from:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "@businessday(1) - 跨越连续节假日",
+			expr:     "@businessday(1)",
+			calendar: holidays,
+			from:     time.Date(2025, 4, 30, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "@businessday(L)",
+			expr:     "@businessday(L)",
+			from:     time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := ParseStandard(tt.expr)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			sched = WithHolidayCalendar(sched, tt.calendar)
+			next := sched.Next(tt.from)
+			if !next.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, next)
+			}
+		})
+	}
+
+	for _, expr := range []string{"0 0 BD0 * *", "0 0 BD24 * *", "0 0 BDx * *", "@businessday()", "@businessday(3"} {
+		if _, err := ParseStandard(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+// TestWithHolidayCalendarDoesNotMutateCache 测试绑定日历不会修改缓存中的共享调度
+func TestWithHolidayCalendarDoesNotMutateCache(t *testing.T) {
+	sched, err := ParseStandard("0 0 BD1 * *")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	bound := WithHolidayCalendar(sched, testHolidays{})
+	if bound == sched {
+		t.Fatal("expected a copy bound to the calendar")
+	}
+	if sched.(*SpecSchedule).calendar != nil {
+		t.Fatal("cached schedule must not be mutated")
+	}
+
+	plain, err := ParseStandard("0 0 1 * *")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if WithHolidayCalendar(plain, testHolidays{}) != plain {
+		t.Fatal("schedule without business day syntax should be returned as is")
+	}
+}
+
+// TestStartupDescriptor 测试 @reboot / @start 描述符解析
+func TestStartupDescriptor(t *testing.T) {
+	tests := []struct {
+		spec  string
+		delay time.Duration
+	}{
+		{"@reboot", 0},
+		{"@start", 0},
+		{"@reboot 30s", 30 * time.Second},
+		{"@start 5m", 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseStandard(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseStandard(%q) failed: %v", tt.spec, err)
+		}
+		delay, ok := StartupDelay(schedule)
+		if !ok || delay != tt.delay {
+			t.Errorf("StartupDelay(%q) = %v, %v; want %v", tt.spec, delay, ok, tt.delay)
+		}
+		if next := schedule.Next(time.Now()); !next.IsZero() {
+			t.Errorf("%q: expected zero Next, got %v", tt.spec, next)
+		}
+	}
+
+	for _, spec := range []string{"@reboot -1s", "@reboot 1s 2s", "@start soon", "@rebooted"} {
+		if _, err := ParseStandard(spec); err == nil {
+			t.Errorf("ParseStandard(%q) expected error", spec)
+		}
+	}
+	if _, err := ParseComposite("@reboot | 0 9 * * *", ParseStandard); err == nil {
+		t.Error("expected startup descriptor to be rejected in composite spec")
+	}
+}
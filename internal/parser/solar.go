@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SolarEvent 太阳事件类型
+type SolarEvent uint8
+
+const (
+	Sunrise SolarEvent = iota // 日出
+	Sunset                    // 日落
+)
+
+// solarSearchDays 极昼 / 极夜期间向后查找的最大天数
+const solarSearchDays = 370
+
+// SolarSchedule 相对指定坐标日出 / 日落时间的调度，在本地按日出方程计算，不依赖网络
+type SolarSchedule struct {
+	Event       SolarEvent
+	Latitude    float64       // 纬度，北纬为正
+	Longitude   float64       // 经度，东经为正
+	Offset      time.Duration // 相对日出 / 日落的偏移，可为负
+	Location    *time.Location
+	locationSet bool
+}
+
+// parseSolar 解析 @sunrise(lat,lon[,offset]) / @sunset(lat,lon[,offset]) 描述符
+func parseSolar(spec string, loc *time.Location) (Schedule, error) {
+	var event SolarEvent
+	args, ok := strings.CutPrefix(spec, "@sunrise(")
+	if !ok {
+		args, _ = strings.CutPrefix(spec, "@sunset(")
+		event = Sunset
+	}
+	args, ok = strings.CutSuffix(args, ")")
+	if !ok {
+		return nil, fmt.Errorf("invalid solar descriptor: %s", spec)
+	}
+
+	parts := strings.Split(args, ",")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, fmt.Errorf("solar descriptor requires latitude, longitude and optional offset: %s", spec)
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("invalid latitude in %s", spec)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("invalid longitude in %s", spec)
+	}
+
+	var offset time.Duration
+	if len(parts) == 3 {
+		offset, err = time.ParseDuration(strings.TrimSpace(parts[2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset in %s: %w", spec, err)
+		}
+		if offset <= -24*time.Hour || offset >= 24*time.Hour {
+			return nil, fmt.Errorf("solar offset must be within 24h: %s", spec)
+		}
+	}
+
+	return &SolarSchedule{
+		Event:     event,
+		Latitude:  lat,
+		Longitude: lon,
+		Offset:    offset,
+		Location:  loc,
+	}, nil
+}
+
+// Next 返回 t 之后最近一次（加偏移后的）日出或日落时间，精确到秒。
+// 极昼 / 极夜期间跳过没有该事件的日期。
+func (s *SolarSchedule) Next(t time.Time) time.Time {
+	origLocation := t.Location()
+
+	loc := s.Location
+	if !s.locationSet || loc == nil {
+		loc = t.Location()
+	}
+
+	local := t.In(loc)
+	for i := -1; i <= solarSearchDays; i++ {
+		date := time.Date(local.Year(), local.Month(), local.Day()+i, 0, 0, 0, 0, time.UTC)
+		event, ok := solarEventTime(date, s.Latitude, s.Longitude, s.Event)
+		if !ok {
+			continue
+		}
+		event = event.Add(s.Offset).Round(time.Second)
+		if event.After(t) {
+			return event.In(origLocation)
+		}
+	}
+	return time.Time{}
+}
+
+// solarEventTime 按日出方程计算指定日期的日出或日落时刻（UTC），误差约一分钟。
+// 该日没有对应事件（极昼 / 极夜）时返回 false。
+func solarEventTime(date time.Time, lat, lon float64, event SolarEvent) (time.Time, bool) {
+	const (
+		j2000      = 2451545.0
+		obliquity  = 23.4397
+		refraction = -0.833 // 大气折射与日面半径修正后的地平高度
+	)
+	rad := math.Pi / 180
+
+	// 自 J2000 起的日数与平太阳正午
+	n := math.Round(date.Sub(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	meanNoon := n - lon/360
+
+	anomaly := math.Mod(357.5291+0.98560028*meanNoon, 360)
+	center := 1.9148*math.Sin(anomaly*rad) + 0.0200*math.Sin(2*anomaly*rad) + 0.0003*math.Sin(3*anomaly*rad)
+	longitude := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := j2000 + meanNoon + 0.0053*math.Sin(anomaly*rad) - 0.0069*math.Sin(2*longitude*rad)
+
+	declination := math.Asin(math.Sin(longitude*rad) * math.Sin(obliquity*rad))
+	cosHourAngle := (math.Sin(refraction*rad) - math.Sin(lat*rad)*math.Sin(declination)) /
+		(math.Cos(lat*rad) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) / rad
+
+	julian := transit - hourAngle/360
+	if event == Sunset {
+		julian = transit + hourAngle/360
+	}
+	return julianToTime(julian), true
+}
+
+// julianToTime 将儒略日转换为 UTC 时间
+func julianToTime(julian float64) time.Time {
+	const unixEpochJulian = 2440587.5
+	return time.Unix(0, 0).UTC().Add(time.Duration((julian - unixEpochJulian) * 24 * float64(time.Hour)))
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSolarAlmanac 对照天文年历（timeanddate.com / NOAA）验证日出日落时间，允许 2 分钟误差
+func TestSolarAlmanac(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		zone string
+		day  string
+		want string // 当地时间
+	}{
+		{"纽约夏至日出", "@sunrise(40.7128,-74.0060)", "America/New_York", "2024-06-20", "05:25"},
+		{"纽约夏至日落", "@sunset(40.7128,-74.0060)", "America/New_York", "2024-06-20", "20:31"},
+		{"伦敦冬至日出", "@sunrise(51.5074,-0.1278)", "Europe/London", "2024-12-21", "08:04"},
+		{"伦敦冬至日落", "@sunset(51.5074,-0.1278)", "Europe/London", "2024-12-21", "15:53"},
+		{"东京元旦日出", "@sunrise(35.6762,139.6503)", "Asia/Tokyo", "2025-01-01", "06:51"},
+		{"东京元旦日落", "@sunset(35.6762,139.6503)", "Asia/Tokyo", "2025-01-01", "16:38"},
+		{"悉尼日出", "@sunrise(-33.8688,151.2093)", "Australia/Sydney", "2024-12-21", "05:41"},
+		{"悉尼日落", "@sunset(-33.8688,151.2093)", "Australia/Sydney", "2024-12-21", "20:05"},
+		{"日落后30分钟", "@sunset(51.5074,-0.1278,+30m)", "Europe/London", "2024-12-21", "16:23"},
+		{"日出前1小时", "@sunrise(51.5074, -0.1278, -1h)", "Europe/London", "2024-12-21", "07:04"},
+	}
+
+	p := MustNewParser(Minute | Hour | Dom | Month | Dow | Descriptor)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Skipf("time zone unavailable: %v", err)
+			}
+			schedule, err := p.Parse("TZ=" + tt.zone + " " + tt.spec)
+			if err != nil {
+				t.Fatalf("parse %q failed: %v", tt.spec, err)
+			}
+
+			from, _ := time.ParseInLocation("2006-01-02", tt.day, loc)
+			want, _ := time.ParseInLocation("2006-01-02 15:04", tt.day+" "+tt.want, loc)
+
+			got := schedule.Next(from)
+			if diff := got.Sub(want); diff < -2*time.Minute || diff > 2*time.Minute {
+				t.Fatalf("Next = %v; want %v (±2m)", got.In(loc), want)
+			}
+			if got.Nanosecond() != 0 {
+				t.Fatalf("expected whole-second result, got %v", got)
+			}
+		})
+	}
+}
+
+// TestSolarConsecutive 测试连续触发逐日推进
+func TestSolarConsecutive(t *testing.T) {
+	schedule, err := ParseStandard("@sunrise(51.5074,-0.1278)")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	next := time.Date(2024, 12, 20, 12, 0, 0, 0, time.UTC)
+	for day := 21; day <= 24; day++ {
+		next = schedule.Next(next)
+		if next.Day() != day || next.Hour() != 8 {
+			t.Fatalf("expected sunrise on Dec %d around 08:0x, got %v", day, next)
+		}
+	}
+}
+
+// TestSolarPolarNight 测试极夜期间跳到下一次日出
+func TestSolarPolarNight(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skipf("time zone unavailable: %v", err)
+	}
+	schedule, err := ParseStandard("TZ=Europe/Oslo @sunrise(69.6492,18.9553)")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	// 特罗姆瑟极夜约持续到 1 月中旬
+	next := schedule.Next(time.Date(2024, 12, 1, 0, 0, 0, 0, oslo)).In(oslo)
+	if next.Year() != 2025 || next.Month() != time.January || next.Day() < 10 || next.Day() > 20 {
+		t.Fatalf("expected first sunrise in mid-January 2025, got %v", next)
+	}
+}
+
+// TestSolarParseErrors 测试非法的日出日落描述符
+func TestSolarParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"@sunrise()",
+		"@sunrise(40.7)",
+		"@sunrise(91,0)",
+		"@sunset(0,181)",
+		"@sunset(abc,0)",
+		"@sunset(0,0,soon)",
+		"@sunset(0,0,+25h)",
+		"@sunrise(0,0,1m,2m)",
+		"@sunrise(0,0",
+	} {
+		if _, err := ParseStandard(spec); err == nil {
+			t.Errorf("ParseStandard(%q) expected error", spec)
+		}
+	}
+}
@@ -23,6 +23,14 @@ type SpecSchedule struct {
 	specificWeekDaysOfWeek map[int]bool // 每月第N个星期X（如 5#3 = 第3个星期五）
 	daysOfMonthRestricted  bool         // Dom 是否受限（不是 *）
 	daysOfWeekRestricted   bool         // Dow 是否受限（不是 *）
+
+	// 营业日语法支持（扩展字段）
+	businessDaysOfMonth    map[int]bool    // 每月第N个营业日（如 BD3）
+	lastBusinessDayOfMonth bool            // 每月最后一个营业日（BDL）
+	calendar               HolidayCalendar // 节假日日历，nil 表示仅排除周末
+
+	// 夏令时切换处理策略，零值沿用默认行为
+	dstPolicy DSTPolicy
 }
 
 // bounds provides a range of acceptable values (plus a map of name to value).
@@ -87,6 +95,19 @@ func (s *SpecSchedule) Next(t time.Time) time.Time {
 		t = t.In(loc)
 	}
 
+	next := s.nextIn(t, loc)
+	if s.dstPolicy != 0 {
+		next = s.applyDSTPolicy(t, next, loc)
+	}
+	if next.IsZero() {
+		return next
+	}
+	return next.In(origLocation)
+}
+
+// nextIn 在指定时区的挂钟时间上查找下一个匹配点，不做夏令时策略修正。
+// 夏令时空隙中的挂钟时间会被跳过，重叠时段的挂钟时间会匹配两次。
+func (s *SpecSchedule) nextIn(t time.Time, loc *time.Location) time.Time {
 	t = t.Add(1*time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
 
 	added := false
@@ -162,7 +183,7 @@ WRAP:
 		}
 	}
 
-	return t.In(origLocation)
+	return t
 }
 
 // dayMatches returns true if the schedule's day-of-week and day-of-month
@@ -170,7 +191,7 @@ WRAP:
 func dayMatches(s *SpecSchedule, t time.Time) bool {
 	// 如果使用了 L/W/# 语法，需要动态计算实际日期
 	if s.hasSpecialDaySyntax() {
-		actualDays := s.calculateActualDaysOfMonth(t.Year(), int(t.Month()))
+		actualDays := s.calculateActualDaysOfMonth(t.Year(), int(t.Month()), t.Location())
 		return slices.Contains(actualDays, t.Day())
 	}
 
@@ -185,17 +206,18 @@ func dayMatches(s *SpecSchedule, t time.Time) bool {
 	return domMatch || dowMatch
 }
 
-// hasSpecialDaySyntax 检查是否使用了 L/W/# 等特殊语法
+// hasSpecialDaySyntax 检查是否使用了 L/W/#/BD 等特殊语法
 func (s *SpecSchedule) hasSpecialDaySyntax() bool {
 	return s.lastDayOfMonth || s.lastWorkdayOfMonth ||
 		len(s.workdaysOfMonth) > 0 ||
+		s.hasBusinessDaySyntax() ||
 		len(s.lastWeekDaysOfWeek) > 0 ||
 		len(s.specificWeekDaysOfWeek) > 0
 }
 
-// calculateActualDaysOfMonth 根据 L/W/# 语法动态计算给定年月的实际日期列表
-// 参考 supercronic/cronexpr 的实现
-func (s *SpecSchedule) calculateActualDaysOfMonth(year, month int) []int {
+// calculateActualDaysOfMonth 根据 L/W/#/BD 语法动态计算给定年月的实际日期列表
+// 参考 supercronic/cronexpr 的实现；loc 用于营业日判断时构造节假日日期
+func (s *SpecSchedule) calculateActualDaysOfMonth(year, month int, loc *time.Location) []int {
 	actualDaysOfMonthMap := make(map[int]bool)
 	firstDayOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	lastDayOfMonth := firstDayOfMonth.AddDate(0, 1, -1)
@@ -237,6 +259,19 @@ func (s *SpecSchedule) calculateActualDaysOfMonth(year, month int) []int {
 				actualDaysOfMonthMap[workdayOfMonth(targetDay, lastDayOfMonth)] = true
 			}
 		}
+
+		// BD3 / BDL - 每月第N个 / 最后一个营业日（排除周末与节假日）
+		if s.hasBusinessDaySyntax() {
+			businessDays := businessDaysInMonth(year, month, loc, s.calendar)
+			for n := range s.businessDaysOfMonth {
+				if n <= len(businessDays) {
+					actualDaysOfMonthMap[businessDays[n-1]] = true
+				}
+			}
+			if s.lastBusinessDayOfMonth && len(businessDays) > 0 {
+				actualDaysOfMonthMap[businessDays[len(businessDays)-1]] = true
+			}
+		}
 	}
 
 	// 处理 day-of-week 字段（Dow）
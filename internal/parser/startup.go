@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StartupSchedule 启动调度：调度器每次启动时触发一次（@reboot / @start），可带延迟。
+// 触发时间由调度器在启动时决定，Next 始终返回零值。
+type StartupSchedule struct {
+	Delay time.Duration
+}
+
+// Next 启动调度不按时间触发，始终返回零值
+func (s *StartupSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
+
+// parseStartup 解析 @reboot / @start 描述符，可选延迟如 "@reboot 30s"
+func parseStartup(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) > 2 {
+		return nil, fmt.Errorf("invalid startup descriptor: %s", spec)
+	}
+
+	var delay time.Duration
+	if len(fields) == 2 {
+		var err error
+		delay, err = parseDuration(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse startup delay %s: %w", spec, err)
+		}
+		if delay < 0 {
+			return nil, fmt.Errorf("startup delay cannot be negative: %s", spec)
+		}
+	}
+	return &StartupSchedule{Delay: delay}, nil
+}
+
+// StartupDelay 判断调度（含截止时间、有效时段包装）是否为启动调度，并返回其延迟
+func StartupDelay(schedule Schedule) (time.Duration, bool) {
+	for {
+		switch typed := schedule.(type) {
+		case *StartupSchedule:
+			return typed.Delay, true
+		case *UntilSchedule:
+			schedule = typed.Schedule
+		case *WindowSchedule:
+			schedule = typed.Schedule
+		default:
+			return 0, false
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package parser
+
+import "time"
+
+// Window 每日（或每周指定星期）的有效时段，按挂钟时间计算
+type Window struct {
+	Start    time.Duration  // 开始时刻，距当天零点的挂钟时长
+	End      time.Duration  // 结束时刻（不含），不大于 Start 时表示跨零点
+	Weekdays uint8          // 生效星期位图（1<<time.Weekday），0 表示每天；跨零点时段按开始当天判断
+	Location *time.Location // 时段所在时区
+}
+
+// WindowSchedule 仅保留落在任一有效时段内的触发点
+type WindowSchedule struct {
+	Schedule Schedule
+	Windows  []Window
+}
+
+// WithWindows 返回限定在有效时段内的调度，windows 为空时原样返回
+func WithWindows(schedule Schedule, windows []Window) Schedule {
+	if len(windows) == 0 {
+		return schedule
+	}
+	return &WindowSchedule{Schedule: schedule, Windows: append([]Window(nil), windows...)}
+}
+
+// Next 返回落在有效时段内的下一个触发点，时段外的触发点被跳过
+func (w *WindowSchedule) Next(t time.Time) time.Time {
+	if _, ok := w.Schedule.(*ConstantDelaySchedule); ok {
+		return w.nextConstantDelay(t)
+	}
+
+	limit := t.AddDate(compositeSearchYears, 0, 0)
+	cur := t
+	for {
+		next := w.Schedule.Next(cur)
+		if next.IsZero() || next.After(limit) {
+			return time.Time{}
+		}
+		if w.contains(next) {
+			return next
+		}
+
+		open := w.nextOpen(next)
+		if open.IsZero() {
+			return time.Time{}
+		}
+		cur = open.Add(-time.Nanosecond)
+	}
+}
+
+// nextConstantDelay 固定间隔调度在时段开始时立即触发，之后按间隔推进
+func (w *WindowSchedule) nextConstantDelay(t time.Time) time.Time {
+	next := w.Schedule.Next(t)
+	open := w.nextOpen(t)
+	if w.contains(next) && (open.IsZero() || !open.Before(next)) {
+		return next
+	}
+	return open
+}
+
+// Clamp 返回不早于 t 且落在有效时段内的最早时刻：t 在时段内时原样返回，否则为下一个时段开始时间
+func (w *WindowSchedule) Clamp(t time.Time) time.Time {
+	if w.contains(t) {
+		return t
+	}
+	return w.nextOpen(t)
+}
+
+func (w *WindowSchedule) mapSchedules(fn func(Schedule) Schedule) Schedule {
+	return &WindowSchedule{Schedule: fn(w.Schedule), Windows: w.Windows}
+}
+
+// contains 判断 t 是否落在任一有效时段内
+func (w *WindowSchedule) contains(t time.Time) bool {
+	for _, window := range w.Windows {
+		if window.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOpen 返回 t 之后最早的时段开始时间
+func (w *WindowSchedule) nextOpen(t time.Time) time.Time {
+	var earliest time.Time
+	for _, window := range w.Windows {
+		open := window.nextOpen(t)
+		if !open.IsZero() && (earliest.IsZero() || open.Before(earliest)) {
+			earliest = open
+		}
+	}
+	return earliest
+}
+
+func (w Window) contains(t time.Time) bool {
+	local := t.In(w.location())
+	clock := time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second +
+		time.Duration(local.Nanosecond())
+
+	if w.Start < w.End {
+		return w.onDay(local.Weekday()) && clock >= w.Start && clock < w.End
+	}
+	// 跨零点：开始当天的后半段，或次日的前半段
+	if clock >= w.Start {
+		return w.onDay(local.Weekday())
+	}
+	return clock < w.End && w.onDay((local.Weekday()+6)%7)
+}
+
+func (w Window) nextOpen(t time.Time) time.Time {
+	loc := w.location()
+	local := t.In(loc)
+	hour, minute, second := int(w.Start/time.Hour), int(w.Start%time.Hour/time.Minute), int(w.Start%time.Minute/time.Second)
+	for i := 0; i <= 7; i++ {
+		open := time.Date(local.Year(), local.Month(), local.Day()+i, hour, minute, second, 0, loc)
+		if open.After(t) && w.onDay(open.Weekday()) {
+			return open
+		}
+	}
+	return time.Time{}
+}
+
+func (w Window) onDay(day time.Weekday) bool {
+	return w.Weekdays == 0 || w.Weekdays&(1<<uint(day)) != 0
+}
+
+func (w Window) location() *time.Location {
+	if w.Location == nil {
+		return time.Local
+	}
+	return w.Location
+}
+
+// UntilSchedule 在截止时间之后不再触发
+type UntilSchedule struct {
+	Schedule Schedule
+	End      time.Time
+}
+
+// Until 返回在 end 之后不再触发的调度，end 为零值时原样返回
+func Until(schedule Schedule, end time.Time) Schedule {
+	if end.IsZero() {
+		return schedule
+	}
+	return &UntilSchedule{Schedule: schedule, End: end}
+}
+
+// Next 返回不晚于截止时间的下一个触发点，超过截止时间返回零值
+func (u *UntilSchedule) Next(t time.Time) time.Time {
+	next := u.Schedule.Next(t)
+	if next.After(u.End) {
+		return time.Time{}
+	}
+	return next
+}
+
+func (u *UntilSchedule) mapSchedules(fn func(Schedule) Schedule) Schedule {
+	return &UntilSchedule{Schedule: fn(u.Schedule), End: u.End}
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWindowSchedule 测试有效时段对触发点的过滤
+func TestWindowSchedule(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+		if err != nil {
+			t.Fatalf("invalid time %q: %v", value, err)
+		}
+		return parsed
+	}
+	weekdays := uint8(0)
+	for day := time.Monday; day <= time.Friday; day++ {
+		weekdays |= 1 << uint(day)
+	}
+
+	tests := []struct {
+		name    string
+		spec    string
+		windows []Window
+		from    string
+		want    []string
+	}{
+		{
+			name:    "每天 08:00-18:00",
+			spec:    "0 */4 * * *",
+			windows: []Window{{Start: 8 * time.Hour, End: 18 * time.Hour, Location: time.UTC}},
+			from:    "2025-06-06 00:00",
+			want:    []string{"2025-06-06 08:00", "2025-06-06 12:00", "2025-06-06 16:00", "2025-06-07 08:00"},
+		},
+		{
+			name:    "结束时刻不含",
+			spec:    "0 * * * *",
+			windows: []Window{{Start: 9 * time.Hour, End: 11 * time.Hour, Location: time.UTC}},
+			from:    "2025-06-06 09:30",
+			want:    []string{"2025-06-06 10:00", "2025-06-07 09:00"},
+		},
+		{
+			name:    "工作日跨零点",
+			spec:    "0 * * * *",
+			windows: []Window{{Start: 23 * time.Hour, End: time.Hour, Weekdays: weekdays, Location: time.UTC}},
+			from:    "2025-06-06 22:30", // 周五
+			want:    []string{"2025-06-06 23:00", "2025-06-07 00:00", "2025-06-09 23:00", "2025-06-10 00:00"},
+		},
+		{
+			name: "多个时段",
+			spec: "0 * * * *",
+			windows: []Window{
+				{Start: 9 * time.Hour, End: 10 * time.Hour, Location: time.UTC},
+				{Start: 14 * time.Hour, End: 15 * time.Hour, Location: time.UTC},
+			},
+			from: "2025-06-06 08:00",
+			want: []string{"2025-06-06 09:00", "2025-06-06 14:00", "2025-06-07 09:00"},
+		},
+		{
+			name:    "固定间隔",
+			spec:    "@every 45m",
+			windows: []Window{{Start: 12 * time.Hour, End: 13 * time.Hour, Location: time.UTC}},
+			from:    "2025-06-06 11:50",
+			want:    []string{"2025-06-06 12:00", "2025-06-06 12:45", "2025-06-07 12:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := MustNewParser(Minute | Hour | Dom | Month | Dow | Descriptor).Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("parse %q failed: %v", tt.spec, err)
+			}
+			schedule := WithWindows(base, tt.windows)
+			next := at(tt.from)
+			for i, want := range tt.want {
+				next = schedule.Next(next)
+				if !next.Equal(at(want)) {
+					t.Fatalf("第 %d 次触发 = %v; want %s", i+1, next, want)
+				}
+			}
+		})
+	}
+}
+
+// TestUntilSchedule 测试截止时间之后不再触发
+func TestUntilSchedule(t *testing.T) {
+	base, err := ParseStandard("0 * * * *")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	end := time.Date(2025, 6, 6, 10, 0, 0, 0, time.UTC)
+	schedule := Until(base, end)
+
+	if next := schedule.Next(end.Add(-30 * time.Minute)); !next.Equal(end) {
+		t.Fatalf("expected %v, got %v", end, next)
+	}
+	if next := schedule.Next(end); !next.IsZero() {
+		t.Fatalf("expected zero time after end, got %v", next)
+	}
+}
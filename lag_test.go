@@ -0,0 +1,129 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darkit/cron/history"
+)
+
+// TestScheduleLagStats 测试按计划触发的执行记录最近、最大、平均开始延迟并超过阈值时告警
+func TestScheduleLagStats(t *testing.T) {
+	logger := &logBuffer{}
+	s := newScheduler()
+	s.logger = logger
+	s.monitor = newMonitor()
+	s.lagWarnThreshold = 500 * time.Millisecond
+
+	task := &Task{ID: "lag-task", Schedule: "0 0 1 1 *", Handler: func(ctx context.Context) {}}
+	if err := s.addTask(task); err != nil {
+		t.Fatalf("add task failed: %v", err)
+	}
+	s.monitor.addTask(task.ID, task.Schedule, time.Now(), nil, "skip")
+	runner := s.tasks[task.ID]
+
+	s.executeTask(runner, TriggerSchedule, time.Now().Add(-2*time.Second))
+	stats, _ := s.monitor.GetStats(task.ID)
+	if stats.LastLag < 2*time.Second || stats.LastLag > 3*time.Second {
+		t.Fatalf("LastLag = %v; want about 2s", stats.LastLag)
+	}
+	if !logger.contains("started") || !logger.contains("late") {
+		t.Fatalf("expected lag warning, got %v", logger.entries)
+	}
+
+	logger.entries = nil
+	s.executeTask(runner, TriggerSchedule, time.Now())
+	stats, _ = s.monitor.GetStats(task.ID)
+	if stats.LastLag > 100*time.Millisecond {
+		t.Fatalf("LastLag = %v; want near zero", stats.LastLag)
+	}
+	if stats.MaxLag < 2*time.Second {
+		t.Fatalf("MaxLag = %v; want at least 2s", stats.MaxLag)
+	}
+	if stats.AvgLag < time.Second || stats.AvgLag > 1500*time.Millisecond {
+		t.Fatalf("AvgLag = %v; want about 1s", stats.AvgLag)
+	}
+	if logger.contains("late") {
+		t.Fatalf("unexpected lag warning below threshold: %v", logger.entries)
+	}
+
+	// 手动触发不计入调度延迟
+	s.executeTask(runner, TriggerManual, time.Time{})
+	again, _ := s.monitor.GetStats(task.ID)
+	if again.LastLag != stats.LastLag || again.AvgLag != stats.AvgLag || again.RunCount != 3 {
+		t.Fatalf("manual run should not affect lag stats: %+v", again)
+	}
+}
+
+// TestScheduleLagInEventAndHistory 测试计划时间与延迟写入事件和历史记录，RunNow 不带计划时间
+func TestScheduleLagInEventAndHistory(t *testing.T) {
+	storage, err := history.NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("create storage failed: %v", err)
+	}
+	recorder, err := history.NewHistoryRecorder(storage)
+	if err != nil {
+		t.Fatalf("create recorder failed: %v", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		events []Event
+	)
+	c := New(
+		WithLogger(&NoOpLogger{}),
+		WithHistoryRecorder(recorder),
+		WithEventHook(func(e Event) {
+			if e.End.IsZero() {
+				return
+			}
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		}),
+	)
+	if err := c.Schedule("lag-event", "* * * * * *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		mu.Lock()
+		n := len(events)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no scheduled run observed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Stop()
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("close recorder failed: %v", err)
+	}
+
+	mu.Lock()
+	event := events[0]
+	mu.Unlock()
+	if event.ScheduledTime.IsZero() || event.Lag < 0 || event.Lag > time.Second {
+		t.Fatalf("unexpected event lag: scheduled=%v lag=%v", event.ScheduledTime, event.Lag)
+	}
+	if !event.Start.Equal(event.ScheduledTime.Add(event.Lag)) {
+		t.Fatalf("Start %v should equal ScheduledTime %v + Lag %v", event.Start, event.ScheduledTime, event.Lag)
+	}
+
+	records, err := storage.Query(history.RecordFilter{TaskID: "lag-event"})
+	if err != nil || len(records) == 0 {
+		t.Fatalf("query history failed: %v (%d records)", err, len(records))
+	}
+	if records[0].ScheduledTime.IsZero() {
+		t.Fatalf("history record should carry scheduled time: %+v", records[0])
+	}
+}
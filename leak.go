@@ -0,0 +1,153 @@
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLeakGracePeriod 泄漏检测的默认宽限期
+const DefaultLeakGracePeriod = 5 * time.Second
+
+// leakCheck 一次待检查的执行
+type leakCheck struct {
+	taskID      string
+	executionID string
+	due         time.Time
+}
+
+// leakDetector 基于 pprof 协程标签的泄漏检测：任务在带有 task_id / execution_id 标签的协程中运行，
+// 其派生的协程继承标签；执行结束并经过宽限期后仍带有该执行标签的协程视为泄漏。
+// 到期的检查合并为一次 goroutine profile 采集
+type leakDetector struct {
+	grace  time.Duration
+	report func(taskID, executionID string, leaked int)
+
+	mu      sync.Mutex
+	pending []leakCheck // 按 due 升序
+	timer   *time.Timer // 无待检查项时为 nil
+	stopped bool
+}
+
+func newLeakDetector(grace time.Duration, report func(taskID, executionID string, leaked int)) *leakDetector {
+	return &leakDetector{grace: grace, report: report}
+}
+
+// labelContext 在上下文已有的标签（task_id、trigger）上追加执行标签，用于设置执行协程的标签
+func (d *leakDetector) labelContext(ctx context.Context, taskID, executionID string) context.Context {
+	return pprof.WithLabels(ctx, pprof.Labels(LogKeyTaskID, taskID, LogKeyExecutionID, executionID))
+}
+
+// watch 登记一次已结束的执行，宽限期后检查残留协程
+func (d *leakDetector) watch(taskID, executionID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+	d.pending = append(d.pending, leakCheck{taskID: taskID, executionID: executionID, due: time.Now().Add(d.grace)})
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.grace, d.check)
+	}
+}
+
+// stop 放弃尚未检查的执行
+func (d *leakDetector) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopped = true
+	d.pending = nil
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// check 取出到期的检查项，采集一次 goroutine profile 并逐个报告
+func (d *leakDetector) check() {
+	now := time.Now()
+	d.mu.Lock()
+	i := 0
+	for i < len(d.pending) && !d.pending[i].due.After(now) {
+		i++
+	}
+	due := d.pending[:i:i]
+	d.pending = d.pending[i:]
+	if len(d.pending) > 0 {
+		d.timer = time.AfterFunc(d.pending[0].due.Sub(now), d.check)
+	} else {
+		d.timer = nil
+	}
+	d.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+	counts := labeledGoroutines(LogKeyExecutionID)
+	for _, c := range due {
+		if leaked := counts[c.executionID]; leaked > 0 {
+			d.report(c.taskID, c.executionID, leaked)
+		}
+	}
+}
+
+// labeledGoroutines 统计当前各标签值对应的协程数，仅统计带有 key 标签的协程
+func labeledGoroutines(key string) map[string]int {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+	return parseGoroutineLabels(&buf, key)
+}
+
+// parseGoroutineLabels 解析 debug=1 格式的 goroutine profile：每组以 "<count> @ <pcs>" 开头，
+// 带标签的组紧随一行 `# labels: {"k":"v", ...}`
+func parseGoroutineLabels(profile *bytes.Buffer, key string) map[string]int {
+	counts := make(map[string]int)
+	needle := strconv.Quote(key) + ":"
+	group := 0
+	scanner := bufio.NewScanner(profile)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if n, _, ok := strings.Cut(line, " @ "); ok {
+			if count, err := strconv.Atoi(n); err == nil {
+				group = count
+			}
+			continue
+		}
+		labels, ok := strings.CutPrefix(line, "# labels: ")
+		if !ok {
+			continue
+		}
+		_, rest, found := strings.Cut(labels, needle)
+		if !found {
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			continue
+		}
+		if value, err := strconv.Unquote(quoted); err == nil {
+			counts[value] += group
+		}
+	}
+	return counts
+}
+
+// reportLeak 记录泄漏统计并输出警告
+func (s *scheduler) reportLeak(taskID, executionID string, leaked int) {
+	if s.monitor != nil {
+		s.monitor.recordLeak(taskID, leaked)
+	}
+	logAttrs(context.Background(), s.logger, slog.LevelWarn, "task leaked goroutines",
+		slog.String(LogKeyTaskID, taskID),
+		slog.String(LogKeyExecutionID, executionID),
+		slog.Int("leaked_goroutines", leaked))
+}
@@ -0,0 +1,98 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestParseGoroutineLabels 测试按标签值汇总 goroutine profile 中的协程数
+func TestParseGoroutineLabels(t *testing.T) {
+	profile := bytes.NewBufferString(`goroutine profile: total 9
+3 @ 0x1 0x2
+# labels: {"execution_id":"a-1", "task_id":"a"}
+#	0x1	main.leak+0x1	/tmp/main.go:10
+
+2 @ 0x3
+# labels: {"task_id":"b", "execution_id":"b \"quoted\""}
+#	0x3	main.other+0x1	/tmp/main.go:20
+
+1 @ 0x4
+# labels: {"execution_id":"a-1"}
+
+3 @ 0x5
+#	0x5	runtime.gopark+0x1	/usr/lib/go/src/runtime/proc.go:1
+`)
+
+	counts := parseGoroutineLabels(profile, LogKeyExecutionID)
+	if len(counts) != 2 || counts["a-1"] != 4 || counts[`b "quoted"`] != 2 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
+
+// TestLeakDetection 测试执行结束后仍存活的派生协程按任务计入泄漏统计，正常任务不受影响
+func TestLeakDetection(t *testing.T) {
+	var buf lockedBuffer
+	c := New(WithSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil))), WithLeakDetection(50*time.Millisecond))
+	defer c.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	if err := c.Schedule("leaky", "0 0 1 1 *", func(ctx context.Context) {
+		for range 3 {
+			go func() {
+				<-release
+			}()
+		}
+	}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("clean", "0 0 1 1 *", func(ctx context.Context) {
+		done := make(chan struct{})
+		go func() {
+			close(done)
+		}()
+		<-done
+	}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	for _, id := range []string{"leaky", "clean"} {
+		if err := c.RunNow(id); err != nil {
+			t.Fatalf("run now failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats, _ := c.GetStats("leaky")
+		if stats.LeakedGoroutines > 0 {
+			if stats.LeakedGoroutines != 3 || stats.LeakedRuns != 1 || stats.PeakLeakedGoroutines != 3 || stats.PeakGoroutines != 0 {
+				t.Fatalf("unexpected leak stats: %+v", stats)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("leak was not detected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if stats, _ := c.GetStats("clean"); stats.LeakedGoroutines != 0 || stats.LeakedRuns != 0 {
+		t.Fatalf("clean task should not report leaks: %+v", stats)
+	}
+	for _, entry := range buf.entries(t) {
+		if entry["msg"] == "task leaked goroutines" {
+			if entry[LogKeyTaskID] != "leaky" || entry["leaked_goroutines"] != float64(3) || entry[LogKeyExecutionID] == nil {
+				t.Fatalf("missing structured attributes: %v", entry)
+			}
+			return
+		}
+	}
+	t.Fatal("expected leak warning")
+}
@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func loadTestLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestJobOptionsLocation(t *testing.T) {
+	tokyo := loadTestLocation(t, "Asia/Tokyo")
+	handler := func(ctx context.Context) {}
+
+	c := New(WithLogger(&NoOpLogger{}))
+	if err := c.Schedule("tokyo", "0 9 * * *", handler, JobOptions{Location: tokyo}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	next, _ := c.NextRun("tokyo")
+	if local := next.In(tokyo); local.Hour() != 9 || local.Minute() != 0 {
+		t.Fatalf("expected 09:00 Asia/Tokyo, got %v", local)
+	}
+
+	info, ok := c.GetTask("tokyo")
+	if !ok || info.TimeZone != "Asia/Tokyo" {
+		t.Fatalf("TaskInfo.TimeZone = %q; want Asia/Tokyo", info.TimeZone)
+	}
+	stats, ok := c.GetStats("tokyo")
+	if !ok || stats.TimeZone != "Asia/Tokyo" {
+		t.Fatalf("Stats.TimeZone = %q; want Asia/Tokyo", stats.TimeZone)
+	}
+}
+
+func TestWithLocationDefault(t *testing.T) {
+	tokyo := loadTestLocation(t, "Asia/Tokyo")
+	berlin := loadTestLocation(t, "Europe/Berlin")
+	handler := func(ctx context.Context) {}
+
+	c := New(WithLogger(&NoOpLogger{}), WithLocation(tokyo))
+	if err := c.Schedule("default", "0 9 * * *", handler); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("prefix", "TZ=Europe/Berlin 0 9 * * *", handler); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("option", "0 9 * * *", handler, JobOptions{Location: berlin}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	want := map[string]string{"default": "Asia/Tokyo", "prefix": "Europe/Berlin", "option": "Europe/Berlin"}
+	for id, zone := range want {
+		info, _ := c.GetTask(id)
+		if info.TimeZone != zone {
+			t.Errorf("task %s TimeZone = %q; want %q", id, info.TimeZone, zone)
+		}
+	}
+
+	plain := New(WithLogger(&NoOpLogger{}))
+	if err := plain.Schedule("local", "0 9 * * *", handler); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if info, _ := plain.GetTask("local"); info.TimeZone != time.Local.String() {
+		t.Fatalf("TimeZone = %q; want %q", info.TimeZone, time.Local.String())
+	}
+}
+
+func TestLocationPrefixConflict(t *testing.T) {
+	tokyo := loadTestLocation(t, "Asia/Tokyo")
+	handler := func(ctx context.Context) {}
+	c := New(WithLogger(&NoOpLogger{}))
+
+	err := c.Schedule("conflict", "TZ=Europe/Berlin 0 9 * * *", handler, JobOptions{Location: tokyo})
+	if err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if _, ok := c.GetStats("conflict"); ok {
+		t.Fatal("rejected task must not be registered in monitor")
+	}
+
+	if err := c.Schedule("same", "CRON_TZ=Asia/Tokyo 0 9 * * *", handler, JobOptions{Location: tokyo}); err != nil {
+		t.Fatalf("matching prefix and option should be accepted: %v", err)
+	}
+	if err := c.Update("same", "TZ=Europe/Berlin 0 10 * * *"); err == nil {
+		t.Fatal("expected update with conflicting prefix to be rejected")
+	}
+}
+
+func TestUpdateKeepsPrefixTimeZone(t *testing.T) {
+	loadTestLocation(t, "Europe/Berlin")
+	handler := func(ctx context.Context) {}
+	c := New(WithLogger(&NoOpLogger{}))
+
+	if err := c.Schedule("berlin", "TZ=Europe/Berlin 30 2 * * *", handler); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Update("berlin", "0 3 * * *"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+
+	info, _ := c.GetTask("berlin")
+	if info.TimeZone != "Europe/Berlin" {
+		t.Fatalf("TimeZone after update = %q; want Europe/Berlin", info.TimeZone)
+	}
+	if stats, _ := c.GetStats("berlin"); stats.TimeZone != "Europe/Berlin" {
+		t.Fatalf("Stats.TimeZone after update = %q; want Europe/Berlin", stats.TimeZone)
+	}
+
+	// 显式传入配置时以新配置为准
+	if err := c.Update("berlin", "0 3 * * *", JobOptions{}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if info, _ := c.GetTask("berlin"); info.TimeZone != time.Local.String() {
+		t.Fatalf("TimeZone after update with options = %q; want %q", info.TimeZone, time.Local.String())
+	}
+}
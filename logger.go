@@ -1,10 +1,29 @@
 package cron
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 )
 
+// 结构化日志的通用属性键，调度器、panic 处理与历史记录统一使用
+const (
+	LogKeyTaskID      = "task_id"
+	LogKeyExecutionID = "execution_id"
+	LogKeyAttempt     = "attempt"
+	LogKeyDuration    = "duration"
+	LogKeyError       = "error"
+)
+
+// StructuredLogger 可选接口：以键值属性输出结构化日志，签名与 (*slog.Logger).LogAttrs 一致。
+// Logger 同时实现该接口时，调度器优先通过它输出，任务 ID、执行 ID、尝试序号等作为独立字段；
+// 否则将属性以 key=value 形式追加到消息末尾，交给 printf 风格的方法输出。
+type StructuredLogger interface {
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
 // DefaultLogger 默认日志实现，使用log/slog
 type DefaultLogger struct {
 	logger *slog.Logger
@@ -17,6 +36,21 @@ func NewDefaultLogger() *DefaultLogger {
 	}
 }
 
+// NewSlogLogger 使用指定的 *slog.Logger 创建日志实现，nil 时使用 slog.Default()
+func NewSlogLogger(logger *slog.Logger) *DefaultLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &DefaultLogger{logger: logger}
+}
+
+// LogAttrs 输出结构化日志
+func (l *DefaultLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if l.logger != nil {
+		l.logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
 // Debugf 输出调试日志
 func (l *DefaultLogger) Debugf(format string, args ...any) {
 	if l.logger != nil {
@@ -59,3 +93,56 @@ func (l *NoOpLogger) Warnf(format string, args ...any) {}
 
 // Errorf 空实现
 func (l *NoOpLogger) Errorf(format string, args ...any) {}
+
+// LogAttrs 空实现
+func (l *NoOpLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+}
+
+// logAttrs 输出一条结构化日志：logger 实现 StructuredLogger 时直接传递属性，
+// 否则按级别降级为 printf 风格调用，属性以 key=value 追加到消息末尾
+func logAttrs(ctx context.Context, logger Logger, level slog.Level, msg string, attrs ...slog.Attr) {
+	if logger == nil {
+		return
+	}
+	if structured, ok := logger.(StructuredLogger); ok {
+		structured.LogAttrs(ctx, level, msg, attrs...)
+		return
+	}
+
+	line := formatAttrs(msg, attrs)
+	switch {
+	case level >= slog.LevelError:
+		logger.Errorf("%s", line)
+	case level >= slog.LevelWarn:
+		logger.Warnf("%s", line)
+	case level >= slog.LevelInfo:
+		logger.Infof("%s", line)
+	default:
+		logger.Debugf("%s", line)
+	}
+}
+
+// formatAttrs 将属性以 key=value 追加到消息末尾，含空白的值加引号
+func formatAttrs(msg string, attrs []slog.Attr) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, attr := range attrs {
+		if attr.Equal(slog.Attr{}) {
+			continue
+		}
+		value := attr.Value.Resolve().String()
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(" " + attr.Key + "=" + value)
+	}
+	return b.String()
+}
+
+// errorAttr 返回错误属性，err 为 nil 时返回空属性（输出时被忽略）
+func errorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.Any(LogKeyError, err)
+}
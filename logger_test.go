@@ -0,0 +1,109 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer 并发安全的日志缓冲区
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// entries 按行解析 JSON 日志
+func (b *lockedBuffer) entries(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// TestSlogLoggerStructuredAttrs 测试 WithSlogLogger 输出带 task_id / execution_id / attempt / duration / error 属性的日志
+func TestSlogLoggerStructuredAttrs(t *testing.T) {
+	var buf lockedBuffer
+	done := make(chan Event, 1)
+	c := New(
+		WithSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithEventHook(func(ev Event) {
+			if ev.Type == EventTaskFinished {
+				done <- ev
+			}
+		}),
+	)
+	defer c.Close()
+
+	err := c.ScheduleJob("flaky", "0 0 1 1 *", &flakyJob{failures: 1}, JobOptions{MaxRetries: 1})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.RunNow("flaky"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+	finished := <-done
+
+	var failed, retrying map[string]any
+	for _, entry := range buf.entries(t) {
+		switch entry["msg"] {
+		case "task attempt failed":
+			failed = entry
+		case "task attempt failed, retrying":
+			retrying = entry
+		}
+	}
+	if failed == nil || retrying == nil {
+		t.Fatalf("expected attempt failure and retry logs, got %v", buf.entries(t))
+	}
+	for _, entry := range []map[string]any{failed, retrying} {
+		if entry[LogKeyTaskID] != "flaky" || entry[LogKeyExecutionID] != finished.ExecutionID ||
+			entry[LogKeyAttempt] != float64(1) || entry[LogKeyError] == nil {
+			t.Fatalf("missing structured attributes: %v", entry)
+		}
+	}
+	if _, ok := failed[LogKeyDuration]; !ok || failed["level"] != "ERROR" {
+		t.Fatalf("attempt failure should report duration at error level: %v", failed)
+	}
+}
+
+// TestLogAttrsFallback 测试 printf 风格 Logger 降级输出 key=value 并按级别分发
+func TestLogAttrsFallback(t *testing.T) {
+	logger := &logBuffer{}
+	logAttrs(context.Background(), logger, slog.LevelWarn, "task attempt failed",
+		slog.String(LogKeyTaskID, "a"),
+		slog.Int(LogKeyAttempt, 2),
+		slog.Duration(LogKeyDuration, 1500*time.Millisecond),
+		errorAttr(errors.New("connection refused")),
+		errorAttr(nil))
+
+	want := `task attempt failed task_id=a attempt=2 duration=1.5s error="connection refused"`
+	if len(logger.entries) != 1 || logger.entries[0] != want {
+		t.Fatalf("entries = %q; want %q", logger.entries, want)
+	}
+
+	logAttrs(context.Background(), nil, slog.LevelError, "ignored")
+	logAttrs(context.Background(), &NoOpLogger{}, slog.LevelError, "ignored")
+}
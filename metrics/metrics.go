@@ -0,0 +1,319 @@
+// Package metrics 以 Prometheus 文本格式导出调度器与任务的运行指标。
+//
+// 指标直接从 Cron.GetAllTasks / GetAllStats 读取，不依赖 Prometheus 客户端库：
+//
+//	http.Handle("/metrics", metrics.NewHandler(c, metrics.WithTaskLabels("team")))
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkit/cron"
+)
+
+// ContentType Prometheus 文本格式的 Content-Type
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Option 指标处理器配置选项
+type Option func(*Handler)
+
+// WithNamespace 设置指标名前缀，默认为 cron
+func WithNamespace(namespace string) Option {
+	return func(h *Handler) {
+		h.namespace = sanitizeName(namespace)
+	}
+}
+
+// WithTaskLabels 将指定的任务标签导出为指标标签，标签名为 label_<key>。
+// 仅导出选定的键，避免标签基数失控；任务未设置该标签时值为空字符串。
+// 键中的非法字符替换为下划线，替换后与先前的键同名（如 a-b 与 a_b）时忽略后者，避免同一序列出现重复标签。
+func WithTaskLabels(keys ...string) Option {
+	return func(h *Handler) {
+		for _, key := range keys {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			name := "label_" + sanitizeName(key)
+			if slices.ContainsFunc(h.labels, func(label taskLabel) bool { return label.name == name }) {
+				continue
+			}
+			h.labels = append(h.labels, taskLabel{key: key, name: name})
+		}
+	}
+}
+
+// taskLabel 导出的任务标签：任务标签键与对应的指标标签名
+type taskLabel struct {
+	key  string
+	name string
+}
+
+// Handler 导出 Prometheus 指标的 http.Handler
+type Handler struct {
+	cron      *cron.Cron
+	namespace string
+	labels    []taskLabel
+	now       func() time.Time
+}
+
+// NewHandler 创建指标处理器
+func NewHandler(c *cron.Cron, opts ...Option) *Handler {
+	h := &Handler{
+		cron:      c,
+		namespace: "cron",
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP 输出全部指标
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := h.Write(&buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// taskSample 单个任务的指标数据
+type taskSample struct {
+	labels string
+	info   *cron.TaskInfo
+	stats  *cron.Stats
+}
+
+// Write 以 Prometheus 文本格式写出全部指标
+func (h *Handler) Write(w io.Writer) error {
+	now := h.now()
+	tasks := h.cron.GetAllTasks()
+	allStats := h.cron.GetAllStats()
+	slices.SortFunc(tasks, func(a, b *cron.TaskInfo) int { return strings.Compare(a.ID, b.ID) })
+
+	samples := make([]taskSample, 0, len(tasks))
+	for _, info := range tasks {
+		stats := allStats[info.ID]
+		if stats == nil {
+			stats = &cron.Stats{ID: info.ID}
+		}
+		samples = append(samples, taskSample{labels: h.taskLabels(info), info: info, stats: stats})
+	}
+
+	mw := &metricWriter{w: w, namespace: h.namespace}
+	mw.family("tasks", "gauge", "Number of registered tasks.")
+	mw.sample("tasks", "", float64(len(tasks)))
+
+	counters := []struct {
+		name, help string
+		value      func(*cron.Stats) int64
+	}{
+		{"task_runs_total", "Total number of task executions.", func(s *cron.Stats) int64 { return s.RunCount }},
+		{"task_success_total", "Total number of successful task executions.", func(s *cron.Stats) int64 { return s.SuccessCount }},
+		{"task_failures_total", "Total number of failed task executions.", func(s *cron.Stats) int64 { return s.FailCount }},
+		{"task_retries_total", "Total number of task retry attempts.", func(s *cron.Stats) int64 { return s.RetryCount }},
+		{"task_skipped_total", "Total number of task triggers skipped by concurrency limits.", func(s *cron.Stats) int64 { return s.SkippedCount }},
+	}
+	for _, counter := range counters {
+		mw.family(counter.name, "counter", counter.help)
+		for _, sample := range samples {
+			mw.sample(counter.name, sample.labels, float64(counter.value(sample.stats)))
+		}
+	}
+
+	bounds := cron.DurationBucketBounds()
+	mw.family("task_duration_seconds", "histogram", "Task execution duration in seconds.")
+	for _, sample := range samples {
+		mw.histogram("task_duration_seconds", sample.labels, bounds, sample.stats.DurationBuckets, sample.stats.TotalDuration)
+	}
+
+	mw.family("task_running", "gauge", "Whether the task is currently running (1) or not (0).")
+	for _, sample := range samples {
+		mw.sample("task_running", sample.labels, boolValue(sample.info.IsRunning))
+	}
+
+	mw.family("task_paused", "gauge", "Whether the task is paused (1) or not (0).")
+	for _, sample := range samples {
+		mw.sample("task_paused", sample.labels, boolValue(sample.info.IsPaused))
+	}
+
+	mw.family("task_next_run_timestamp_seconds", "gauge", "Unix timestamp of the next planned run.")
+	for _, sample := range samples {
+		if !sample.info.NextRun.IsZero() {
+			mw.sample("task_next_run_timestamp_seconds", sample.labels, unixSeconds(sample.info.NextRun))
+		}
+	}
+
+	mw.family("task_last_run_timestamp_seconds", "gauge", "Unix timestamp of the last finished or skipped run.")
+	for _, sample := range samples {
+		if !sample.stats.LastRun.IsZero() {
+			mw.sample("task_last_run_timestamp_seconds", sample.labels, unixSeconds(sample.stats.LastRun))
+		}
+	}
+
+	mw.family("task_schedule_lag_seconds", "gauge", "Seconds the task is behind its planned run time, 0 when on schedule.")
+	for _, sample := range samples {
+		mw.sample("task_schedule_lag_seconds", sample.labels, scheduleLag(sample.info, now).Seconds())
+	}
+
+	lagGauges := []struct {
+		name, help string
+		value      func(*cron.Stats) time.Duration
+	}{
+		{"task_last_start_lag_seconds", "Start delay of the most recent scheduled run relative to its planned time.", func(s *cron.Stats) time.Duration { return s.LastLag }},
+		{"task_max_start_lag_seconds", "Maximum observed start delay of scheduled runs.", func(s *cron.Stats) time.Duration { return s.MaxLag }},
+		{"task_avg_start_lag_seconds", "Average start delay of scheduled runs.", func(s *cron.Stats) time.Duration { return s.AvgLag }},
+	}
+	for _, gauge := range lagGauges {
+		mw.family(gauge.name, "gauge", gauge.help)
+		for _, sample := range samples {
+			mw.sample(gauge.name, sample.labels, gauge.value(sample.stats).Seconds())
+		}
+	}
+
+	cache := cron.GetParserCacheStats()
+	mw.family("parser_cache_hits_total", "counter", "Total number of parser cache hits.")
+	mw.sample("parser_cache_hits_total", "", float64(cache.Hits))
+	mw.family("parser_cache_misses_total", "counter", "Total number of parser cache misses.")
+	mw.sample("parser_cache_misses_total", "", float64(cache.Misses))
+	mw.family("parser_cache_evictions_total", "counter", "Total number of parser cache evictions.")
+	mw.sample("parser_cache_evictions_total", "", float64(cache.Evictions))
+	mw.family("parser_cache_entries", "gauge", "Number of cached parsed schedules.")
+	mw.sample("parser_cache_entries", "", float64(cache.Entries))
+
+	return mw.err
+}
+
+// taskLabels 生成任务的标签串，形如 task_id="a",label_team="x"
+func (h *Handler) taskLabels(info *cron.TaskInfo) string {
+	var b strings.Builder
+	b.WriteString(`task_id="`)
+	b.WriteString(escapeLabelValue(info.ID))
+	b.WriteByte('"')
+	for _, label := range h.labels {
+		b.WriteByte(',')
+		b.WriteString(label.name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(info.Labels[label.key]))
+		b.WriteByte('"')
+	}
+	return b.String()
+}
+
+// scheduleLag 计算任务落后计划的时长：未暂停、未运行且已过计划时间时为 now - NextRun
+func scheduleLag(info *cron.TaskInfo, now time.Time) time.Duration {
+	if info.IsPaused || info.IsRunning || info.NextRun.IsZero() || !now.After(info.NextRun) {
+		return 0
+	}
+	return now.Sub(info.NextRun)
+}
+
+// metricWriter 按 Prometheus 文本格式写出指标，记录首个写入错误
+type metricWriter struct {
+	w         io.Writer
+	namespace string
+	err       error
+}
+
+func (mw *metricWriter) printf(format string, args ...any) {
+	if mw.err != nil {
+		return
+	}
+	_, mw.err = fmt.Fprintf(mw.w, format, args...)
+}
+
+func (mw *metricWriter) name(name string) string {
+	if mw.namespace == "" {
+		return name
+	}
+	return mw.namespace + "_" + name
+}
+
+// family 写出 HELP 与 TYPE 行
+func (mw *metricWriter) family(name, typ, help string) {
+	mw.printf("# HELP %s %s\n# TYPE %s %s\n", mw.name(name), help, mw.name(name), typ)
+}
+
+// sample 写出一个样本
+func (mw *metricWriter) sample(name, labels string, value float64) {
+	if labels != "" {
+		labels = "{" + labels + "}"
+	}
+	mw.printf("%s%s %s\n", mw.name(name), labels, formatFloat(value))
+}
+
+// histogram 写出累积桶、_sum 与 _count，buckets 为非累积计数，末项为超出上界的次数
+func (mw *metricWriter) histogram(name, labels string, bounds []time.Duration, buckets []int64, total time.Duration) {
+	var cumulative int64
+	for i, bound := range bounds {
+		if i < len(buckets) {
+			cumulative += buckets[i]
+		}
+		mw.sample(name+"_bucket", joinLabels(labels, `le="`+formatFloat(bound.Seconds())+`"`), float64(cumulative))
+	}
+	if len(buckets) > len(bounds) {
+		cumulative += buckets[len(bounds)]
+	}
+	mw.sample(name+"_bucket", joinLabels(labels, `le="+Inf"`), float64(cumulative))
+	mw.sample(name+"_sum", labels, total.Seconds())
+	mw.sample(name+"_count", labels, float64(cumulative))
+}
+
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
+
+// escapeLabelValue 转义标签值中的反斜杠、双引号与换行
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// sanitizeName 将任意字符串转换为合法的指标名 / 标签名片段
+func sanitizeName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
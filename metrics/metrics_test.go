@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darkit/cron"
+)
+
+// scrape 请求指标并返回响应体
+func scrape(t *testing.T, h http.Handler) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Fatalf("unexpected content type %q", got)
+	}
+	return w.Body.String()
+}
+
+// TestHandlerTaskMetrics 测试任务计数、直方图、暂停与运行状态指标
+func TestHandlerTaskMetrics(t *testing.T) {
+	c := cron.New(cron.WithLogger(&cron.NoOpLogger{}))
+	defer c.Stop()
+
+	done := make(chan struct{}, 1)
+	err := c.Schedule("report", "0 0 1 1 *", func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		done <- struct{}{}
+	}, cron.JobOptions{Labels: map[string]string{"team": `ops "core"`, "env": "prod"}})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("cleanup", "0 0 1 1 *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Pause("cleanup"); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.RunNow("report"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+	<-done
+	waitFor(t, func() bool {
+		stats, ok := c.GetStats("report")
+		return ok && stats.RunCount == 1 && !stats.IsRunning
+	})
+
+	body := scrape(t, NewHandler(c, WithTaskLabels("team")))
+	report := `task_id="report",label_team="ops \"core\""`
+	for _, want := range []string{
+		"# TYPE cron_task_runs_total counter",
+		"cron_tasks 2",
+		"cron_task_runs_total{" + report + "} 1",
+		"cron_task_success_total{" + report + "} 1",
+		"cron_task_failures_total{" + report + "} 0",
+		"# TYPE cron_task_duration_seconds histogram",
+		"cron_task_duration_seconds_bucket{" + report + `,le="0.01"} 0`,
+		"cron_task_duration_seconds_bucket{" + report + `,le="0.25"} 1`,
+		"cron_task_duration_seconds_bucket{" + report + `,le="+Inf"} 1`,
+		"cron_task_duration_seconds_count{" + report + "} 1",
+		`cron_task_paused{task_id="cleanup",label_team=""} 1`,
+		"cron_task_running{" + report + "} 0",
+		"cron_task_next_run_timestamp_seconds{" + report + "} ",
+		"cron_task_last_run_timestamp_seconds{" + report + "} ",
+		"cron_task_schedule_lag_seconds{" + report + "} 0",
+		"# TYPE cron_task_max_start_lag_seconds gauge",
+		"cron_task_last_start_lag_seconds{" + report + "} 0",
+		"# TYPE cron_parser_cache_hits_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in output:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "label_env") {
+		t.Error("unselected task labels should not be exported")
+	}
+}
+
+// TestScheduleLag 测试已过计划时间的任务报告落后时长
+func TestScheduleLag(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		info cron.TaskInfo
+		want time.Duration
+	}{
+		{"按计划", cron.TaskInfo{NextRun: now.Add(time.Minute)}, 0},
+		{"落后", cron.TaskInfo{NextRun: now.Add(-3 * time.Second)}, 3 * time.Second},
+		{"暂停", cron.TaskInfo{NextRun: now.Add(-3 * time.Second), IsPaused: true}, 0},
+		{"运行中", cron.TaskInfo{NextRun: now.Add(-3 * time.Second), IsRunning: true}, 0},
+		{"无计划", cron.TaskInfo{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scheduleLag(&tt.info, now); got != tt.want {
+				t.Fatalf("scheduleLag = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNamespaceAndSanitize 测试指标前缀与非法标签名字符替换
+func TestNamespaceAndSanitize(t *testing.T) {
+	c := cron.New(cron.WithLogger(&cron.NoOpLogger{}))
+	err := c.Schedule("a", "0 0 1 1 *", func(ctx context.Context) {}, cron.JobOptions{Labels: map[string]string{"app.kubernetes.io/name": "x\ny"}})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	body := scrape(t, NewHandler(c, WithNamespace("my-app"), WithTaskLabels("app.kubernetes.io/name")))
+	if !strings.Contains(body, `my_app_task_runs_total{task_id="a",label_app_kubernetes_io_name="x\ny"} 0`) {
+		t.Fatalf("unexpected output:\n%s", body)
+	}
+}
+
+// TestTaskLabelCollision 测试清洗后同名的标签键只导出第一个
+func TestTaskLabelCollision(t *testing.T) {
+	c := cron.New(cron.WithLogger(&cron.NoOpLogger{}))
+	labels := map[string]string{"a-b": "1", "a_b": "2", "团队": "x", "部门": "y"}
+	if err := c.Schedule("a", "0 0 1 1 *", func(ctx context.Context) {}, cron.JobOptions{Labels: labels}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	body := scrape(t, NewHandler(c, WithTaskLabels("a-b", "a_b", "团队", "部门")))
+	if !strings.Contains(body, `cron_task_runs_total{task_id="a",label_a_b="1",label___="x"} 0`) {
+		t.Fatalf("unexpected output:\n%s", body)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -1,44 +1,104 @@
 package cron
 
 import (
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/darkit/cron/history"
 )
 
+// durationBucketBounds 执行时长直方图的桶上界，与 Prometheus 默认桶一致
+var durationBucketBounds = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// DurationBucketBounds 返回执行时长直方图的桶上界，Stats.DurationBuckets 按此顺序计数
+func DurationBucketBounds() []time.Duration {
+	return slices.Clone(durationBucketBounds)
+}
+
 // Stats 任务简化统计信息
 type Stats struct {
-	ID             string            `json:"id"`              // 任务ID
-	Schedule       string            `json:"schedule"`        // 调度表达式
-	RunCount       int64             `json:"run_count"`       // 运行次数
-	SuccessCount   int64             `json:"success_count"`   // 成功次数
-	FailCount      int64             `json:"fail_count"`      // 失败次数
-	RetryCount     int64             `json:"retry_count"`     // 重试总次数
-	SkippedCount   int64             `json:"skipped_count"`   // 因并发限制被跳过的次数
-	TotalDuration  time.Duration     `json:"total_duration"`  // 累计执行时长
-	MinDuration    time.Duration     `json:"min_duration"`    // 最小执行时长
-	MaxDuration    time.Duration     `json:"max_duration"`    // 最大执行时长
-	PeakGoroutines int64             `json:"peak_goroutines"` // 峰值协程数
-	Labels         map[string]string `json:"labels"`          // 任务标签
-	LastRun        time.Time         `json:"last_run"`        // 最后运行时间
-	IsRunning      bool              `json:"is_running"`      // 是否正在运行
-	CreatedAt      time.Time         `json:"created_at"`      // 创建时间
-	PauseUntil     time.Time         `json:"pause_until"`     // 暂停到期时间
-	MisfirePolicy  string            `json:"misfire_policy"`  // Misfire 策略
-	HasLastResult  bool              `json:"has_last_result"`
-	LastRunSuccess bool              `json:"last_run_success"`
-	LastError      string            `json:"last_error"`
+	ID                string            `json:"id"`                  // 任务ID
+	Schedule          string            `json:"schedule"`            // 调度表达式
+	RunCount          int64             `json:"run_count"`           // 运行次数
+	SuccessCount      int64             `json:"success_count"`       // 成功次数
+	FailCount         int64             `json:"fail_count"`          // 失败次数
+	RetryCount        int64             `json:"retry_count"`         // 重试总次数
+	SkippedCount      int64             `json:"skipped_count"`       // 因并发限制被跳过的次数
+	TotalDuration     time.Duration     `json:"total_duration"`      // 累计执行时长
+	MinDuration       time.Duration     `json:"min_duration"`        // 最小执行时长
+	MaxDuration       time.Duration     `json:"max_duration"`        // 最大执行时长
+	DurationBuckets   []int64           `json:"duration_buckets"`    // 执行时长分布，按 DurationBucketBounds 分桶，末项为超出上界的次数
+	P50Duration       time.Duration     `json:"p50_duration"`        // 执行时长中位数（近似值）
+	P90Duration       time.Duration     `json:"p90_duration"`        // 执行时长 90 分位（近似值）
+	P99Duration       time.Duration     `json:"p99_duration"`        // 执行时长 99 分位（近似值）
+	RecentAvgDuration time.Duration     `json:"recent_avg_duration"` // 最近 64 次执行的平均时长
+	LastLag           time.Duration     `json:"last_lag"`            // 最近一次按计划触发的开始延迟
+	MaxLag            time.Duration     `json:"max_lag"`             // 最大开始延迟
+	AvgLag            time.Duration     `json:"avg_lag"`             // 平均开始延迟
+	PeakGoroutines    int64             `json:"peak_goroutines"`     // 峰值协程数
+	Labels            map[string]string `json:"labels"`              // 任务标签
+	LastRun           time.Time         `json:"last_run"`            // 最后运行时间
+	IsRunning         bool              `json:"is_running"`          // 是否正在运行
+	CreatedAt         time.Time         `json:"created_at"`          // 创建时间
+	PauseUntil        time.Time         `json:"pause_until"`         // 暂停到期时间
+	MisfirePolicy     string            `json:"misfire_policy"`      // Misfire 策略
+	TimeZone          string            `json:"time_zone"`           // 生效时区名称
+	HasLastResult     bool              `json:"has_last_result"`
+	LastRunSuccess    bool              `json:"last_run_success"`
+	LastError         string            `json:"last_error"`
+
+	LastSuccess         time.Time     `json:"last_success"`          // 最近一次成功时间
+	ExpectSuccessWithin time.Duration `json:"expect_success_within"` // 成功 SLA，0 表示未设置
+	Overdue             bool          `json:"overdue"`               // 超过 SLA 未成功
+
+	LeakedGoroutines     int64 `json:"leaked_goroutines"`      // 累计泄漏协程数，需启用 WithLeakDetection
+	LeakedRuns           int64 `json:"leaked_runs"`            // 发生泄漏的执行次数
+	PeakLeakedGoroutines int64 `json:"peak_leaked_goroutines"` // 单次执行泄漏协程数的峰值
+
+	ResourceSamples int64         `json:"resource_samples"` // 资源采样次数，需启用 WithResourceSampling
+	LastWallTime    time.Duration `json:"last_wall_time"`   // 最近一次采样的执行耗时，不含重试等待
+	AllocSamples    int64         `json:"alloc_samples"`    // 分配量可归属于该任务的采样次数
+	LastAllocBytes  uint64        `json:"last_alloc_bytes"` // 最近一次可归属采样的堆分配字节数
+	AvgAllocBytes   uint64        `json:"avg_alloc_bytes"`  // 平均每次执行的堆分配字节数
+	MaxAllocBytes   uint64        `json:"max_alloc_bytes"`  // 单次执行的最大堆分配字节数
+}
+
+// taskSamples 任务的累计样本，不直接暴露在 Stats 中
+type taskSamples struct {
+	durations  durationHistogram
+	lagTotal   time.Duration
+	lagCount   int64
+	allocTotal uint64
 }
 
 // Monitor 简化的任务监控器
 type Monitor struct {
-	stats map[string]*Stats
-	mu    sync.RWMutex
+	stats   map[string]*Stats
+	samples map[string]*taskSamples // 每个任务的时长直方图与延迟累计，用于计算分位数与平均值
+	mu      sync.RWMutex
+
+	slaTasks atomic.Int64 // 设置了成功 SLA 的任务数，为 0 时 checkOverdue 直接返回
 }
 
 // newMonitor 创建新的任务监控器
 func newMonitor() *Monitor {
 	return &Monitor{
-		stats: make(map[string]*Stats),
+		stats:   make(map[string]*Stats),
+		samples: make(map[string]*taskSamples),
 	}
 }
 
@@ -47,6 +107,9 @@ func (m *Monitor) addTask(id, schedule string, createdAt time.Time, labels map[s
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if old, exists := m.stats[id]; exists {
+		m.trackSLA(old.ExpectSuccessWithin, 0)
+	}
 	m.stats[id] = &Stats{
 		ID:            id,
 		Schedule:      schedule,
@@ -54,6 +117,7 @@ func (m *Monitor) addTask(id, schedule string, createdAt time.Time, labels map[s
 		Labels:        cloneLabels(labels),
 		MisfirePolicy: misfire,
 	}
+	m.samples[id] = &taskSamples{}
 }
 
 // removeTask 从监控中移除任务
@@ -61,7 +125,11 @@ func (m *Monitor) removeTask(id string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if stats, exists := m.stats[id]; exists {
+		m.trackSLA(stats.ExpectSuccessWithin, 0)
+	}
 	delete(m.stats, id)
+	delete(m.samples, id)
 }
 
 // recordExecution 记录任务执行
@@ -103,6 +171,21 @@ func (m *Monitor) recordExecutionResult(id string, finishedAt time.Time, duratio
 		stats.MaxDuration = duration
 	}
 
+	if stats.DurationBuckets == nil {
+		stats.DurationBuckets = make([]int64, len(durationBucketBounds)+1)
+	}
+	bucket, _ := slices.BinarySearch(durationBucketBounds, duration)
+	stats.DurationBuckets[bucket]++
+
+	if samples := m.samples[id]; samples != nil {
+		histogram := &samples.durations
+		histogram.record(duration)
+		stats.P50Duration = histogram.quantile(0.5)
+		stats.P90Duration = histogram.quantile(0.9)
+		stats.P99Duration = histogram.quantile(0.99)
+		stats.RecentAvgDuration = histogram.recentAverage()
+	}
+
 	if finishedAt.IsZero() {
 		finishedAt = time.Now()
 	}
@@ -110,6 +193,52 @@ func (m *Monitor) recordExecutionResult(id string, finishedAt time.Time, duratio
 	stats.HasLastResult = true
 	stats.LastRunSuccess = success
 	stats.LastError = lastError
+	if success {
+		stats.LastSuccess = finishedAt
+		stats.Overdue = false
+	}
+}
+
+// recordLag 记录按计划触发的执行相对计划时间的开始延迟
+func (m *Monitor) recordLag(id string, lag time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, exists := m.stats[id]
+	samples := m.samples[id]
+	if !exists || samples == nil {
+		return
+	}
+
+	lag = max(lag, 0)
+	samples.lagTotal += lag
+	samples.lagCount++
+	stats.LastLag = lag
+	stats.MaxLag = max(stats.MaxLag, lag)
+	stats.AvgLag = samples.lagTotal / time.Duration(samples.lagCount)
+}
+
+// recordResources 记录一次执行的资源采样
+func (m *Monitor) recordResources(id string, usage *history.ResourceUsage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, exists := m.stats[id]
+	samples := m.samples[id]
+	if !exists || samples == nil || usage == nil {
+		return
+	}
+
+	stats.ResourceSamples++
+	stats.LastWallTime = usage.WallTime
+	if !usage.AllocMeasured {
+		return
+	}
+	samples.allocTotal += usage.AllocBytes
+	stats.AllocSamples++
+	stats.LastAllocBytes = usage.AllocBytes
+	stats.MaxAllocBytes = max(stats.MaxAllocBytes, usage.AllocBytes)
+	stats.AvgAllocBytes = samples.allocTotal / uint64(stats.AllocSamples)
 }
 
 // recordSkip 记录因并发限制被跳过的次数
@@ -141,6 +270,20 @@ func (m *Monitor) recordGoroutines(id string, goroutines int64) {
 	}
 }
 
+// recordLeak 记录一次执行结束后残留的协程数
+func (m *Monitor) recordLeak(id string, leaked int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, exists := m.stats[id]
+	if !exists || leaked <= 0 {
+		return
+	}
+	stats.LeakedGoroutines += int64(leaked)
+	stats.LeakedRuns++
+	stats.PeakLeakedGoroutines = max(stats.PeakLeakedGoroutines, int64(leaked))
+}
+
 // setRunning 设置任务运行状态
 func (m *Monitor) setRunning(id string, running bool) {
 	m.mu.Lock()
@@ -171,6 +314,16 @@ func (m *Monitor) updateSchedule(id, schedule string) {
 	}
 }
 
+// setTimeZone 记录任务生效的时区名称
+func (m *Monitor) setTimeZone(id, timeZone string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if stats, exists := m.stats[id]; exists {
+		stats.TimeZone = timeZone
+	}
+}
+
 // updateTaskMeta 更新任务标签和 Misfire 策略
 func (m *Monitor) updateTaskMeta(id string, labels map[string]string, misfire string) {
 	m.mu.Lock()
@@ -182,6 +335,85 @@ func (m *Monitor) updateTaskMeta(id string, labels map[string]string, misfire st
 	}
 }
 
+// setExpectSuccessWithin 记录任务的成功 SLA，取消 SLA 时同时清除超期标记
+func (m *Monitor) setExpectSuccessWithin(id string, within time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if stats, exists := m.stats[id]; exists {
+		m.trackSLA(stats.ExpectSuccessWithin, within)
+		stats.ExpectSuccessWithin = within
+		if within <= 0 {
+			stats.Overdue = false
+		}
+	}
+}
+
+// overdueTask 新进入超期状态的任务
+type overdueTask struct {
+	id          string
+	labels      map[string]string
+	lastSuccess time.Time
+	within      time.Duration
+}
+
+// checkOverdue 按 SLA 重新计算各任务的超期标记，起算点为最近一次成功时间，
+// 从未成功时为任务创建时间。返回本次由未超期变为超期的任务
+func (m *Monitor) checkOverdue(now time.Time) []overdueTask {
+	if m.slaTasks.Load() == 0 {
+		return nil
+	}
+
+	// 先在读锁下找出超期状态需要变化的任务，避免每个检查间隔都与执行记录争用写锁
+	m.mu.RLock()
+	var changed []string
+	for id, stats := range m.stats {
+		if stats.ExpectSuccessWithin > 0 && isOverdue(stats, now) != stats.Overdue {
+			changed = append(changed, id)
+		}
+	}
+	m.mu.RUnlock()
+	if len(changed) == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var became []overdueTask
+	for _, id := range changed {
+		stats, exists := m.stats[id]
+		if !exists || stats.ExpectSuccessWithin <= 0 {
+			continue
+		}
+		overdue := isOverdue(stats, now)
+		if overdue && !stats.Overdue {
+			became = append(became, overdueTask{id: id, labels: cloneLabels(stats.Labels), lastSuccess: stats.LastSuccess, within: stats.ExpectSuccessWithin})
+		}
+		stats.Overdue = overdue
+	}
+	return became
+}
+
+// isOverdue 判断任务在 now 时是否已超过成功 SLA，从未成功时从创建时间起算
+func isOverdue(stats *Stats, now time.Time) bool {
+	since := stats.LastSuccess
+	if since.IsZero() {
+		since = stats.CreatedAt
+	}
+	return now.Sub(since) > stats.ExpectSuccessWithin
+}
+
+// trackSLA 在任务的成功 SLA 由 old 变为 within 时更新 slaTasks，调用方需持有写锁
+func (m *Monitor) trackSLA(old, within time.Duration) {
+	switch {
+	case old <= 0 && within > 0:
+		m.slaTasks.Add(1)
+	case old > 0 && within <= 0:
+		m.slaTasks.Add(-1)
+	}
+}
+
 // GetStats 获取指定任务的统计信息
 func (m *Monitor) GetStats(id string) (*Stats, bool) {
 	m.mu.RLock()
@@ -192,9 +424,15 @@ func (m *Monitor) GetStats(id string) (*Stats, bool) {
 		return nil, false
 	}
 
+	return cloneStats(stats), true
+}
+
+// cloneStats 深拷贝统计信息，避免调用方持有内部切片与映射
+func cloneStats(stats *Stats) *Stats {
 	statsCopy := *stats
 	statsCopy.Labels = cloneLabels(stats.Labels)
-	return &statsCopy, true
+	statsCopy.DurationBuckets = slices.Clone(stats.DurationBuckets)
+	return &statsCopy
 }
 
 // GetAllStats 获取所有任务的统计信息
@@ -204,9 +442,7 @@ func (m *Monitor) GetAllStats() map[string]*Stats {
 
 	result := make(map[string]*Stats, len(m.stats))
 	for id, stats := range m.stats {
-		statsCopy := *stats
-		statsCopy.Labels = cloneLabels(stats.Labels)
-		result[id] = &statsCopy
+		result[id] = cloneStats(stats)
 	}
 
 	return result
@@ -449,3 +449,57 @@ func TestMonitorRecordExecutionUsesProvidedFinishedAt(t *testing.T) {
 		t.Fatalf("LastRun = %v; want %v", stats.LastRun, finishedAt)
 	}
 }
+
+// TestMonitor_DurationBuckets 测试执行时长按桶上界计数，且返回副本
+func TestMonitor_DurationBuckets(t *testing.T) {
+	m := newMonitor()
+	m.addTask("bucket-task", "* * * * *", time.Now(), nil, "skip")
+
+	for _, d := range []time.Duration{time.Millisecond, 5 * time.Millisecond, 7 * time.Millisecond, time.Minute} {
+		m.recordExecution("bucket-task", time.Now(), d, true, 0)
+	}
+
+	stats, _ := m.GetStats("bucket-task")
+	bounds := DurationBucketBounds()
+	if len(stats.DurationBuckets) != len(bounds)+1 {
+		t.Fatalf("DurationBuckets 长度 = %d; want %d", len(stats.DurationBuckets), len(bounds)+1)
+	}
+	if stats.DurationBuckets[0] != 2 || stats.DurationBuckets[1] != 1 || stats.DurationBuckets[len(bounds)] != 1 {
+		t.Fatalf("DurationBuckets = %v", stats.DurationBuckets)
+	}
+
+	stats.DurationBuckets[0] = 100
+	again, _ := m.GetStats("bucket-task")
+	if again.DurationBuckets[0] != 2 {
+		t.Fatal("GetStats 应返回 DurationBuckets 副本")
+	}
+}
+
+// TestMonitor_DurationPercentiles 测试 Stats 报告分位数与近期平均时长
+func TestMonitor_DurationPercentiles(t *testing.T) {
+	m := newMonitor()
+	m.addTask("pct-task", "* * * * *", time.Now(), nil, "skip")
+
+	for range 98 {
+		m.recordExecution("pct-task", time.Now(), 20*time.Millisecond, true, 0)
+	}
+	m.recordExecution("pct-task", time.Now(), 5*time.Second, true, 0)
+	m.recordExecution("pct-task", time.Now(), 5*time.Second, true, 0)
+
+	stats, _ := m.GetStats("pct-task")
+	if stats.P50Duration != 20*time.Millisecond || stats.P90Duration != 20*time.Millisecond {
+		t.Errorf("P50 / P90 = %v / %v; want 20ms", stats.P50Duration, stats.P90Duration)
+	}
+	if stats.P99Duration < 4500*time.Millisecond {
+		t.Errorf("P99 = %v; want about 5s", stats.P99Duration)
+	}
+	want := (62*20*time.Millisecond + 2*5*time.Second) / 64
+	if stats.RecentAvgDuration != want {
+		t.Errorf("RecentAvgDuration = %v; want %v", stats.RecentAvgDuration, want)
+	}
+
+	m.removeTask("pct-task")
+	if _, ok := m.samples["pct-task"]; ok {
+		t.Error("removeTask should drop the duration histogram")
+	}
+}
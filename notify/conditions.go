@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkit/cron"
+)
+
+// Finding 条件命中结果
+type Finding struct {
+	TaskID  string
+	Labels  map[string]string
+	Message string
+	Event   cron.Event // 触发命中的事件，周期检查命中时为零值
+}
+
+// Condition 告警条件，Observe 处理一个事件并返回是否命中
+type Condition interface {
+	Observe(ev cron.Event) (Finding, bool)
+}
+
+// Sweeper 需要周期检查的条件额外实现该接口，Sweep 返回当前命中的任务
+type Sweeper interface {
+	Sweep(now time.Time) []Finding
+}
+
+// seeder 需要在启动时登记已有任务作为基线的条件
+type seeder interface {
+	seed(taskID string, labels map[string]string, now time.Time)
+}
+
+// ConditionFunc 无状态条件的函数适配器
+type ConditionFunc func(ev cron.Event) (Finding, bool)
+
+// Observe 调用函数本身
+func (f ConditionFunc) Observe(ev cron.Event) (Finding, bool) {
+	return f(ev)
+}
+
+// OnEvent 在出现指定类型的事件时命中
+func OnEvent(types ...cron.EventType) Condition {
+	types = slices.Clone(types)
+	return ConditionFunc(func(ev cron.Event) (Finding, bool) {
+		if !slices.Contains(types, ev.Type) {
+			return Finding{}, false
+		}
+		return findingFor(ev, fmt.Sprintf("task %s: %s", ev.TaskID, ev.Type)), true
+	})
+}
+
+// AutoPaused 在任务因连续失败被熔断自动暂停时命中
+func AutoPaused() Condition {
+	return ConditionFunc(func(ev cron.Event) (Finding, bool) {
+		if ev.Type != cron.EventTaskAutoPaused {
+			return Finding{}, false
+		}
+		msg := fmt.Sprintf("task %s was paused by the circuit breaker until %s", ev.TaskID, ev.PauseUntil.Format(time.RFC3339))
+		return findingFor(ev, msg), true
+	})
+}
+
+// ConsecutiveFailures 在任务连续 n 次执行失败时命中；之后直到再次成功前不重复命中
+func ConsecutiveFailures(n int) Condition {
+	return &consecutiveFailures{threshold: max(n, 1), counts: make(map[string]int)}
+}
+
+type consecutiveFailures struct {
+	threshold int
+	mu        sync.Mutex
+	counts    map[string]int
+}
+
+func (c *consecutiveFailures) Observe(ev cron.Event) (Finding, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch ev.Type {
+	case cron.EventTaskFinished:
+		if ev.Success {
+			delete(c.counts, ev.TaskID)
+			return Finding{}, false
+		}
+		c.counts[ev.TaskID]++
+		if c.counts[ev.TaskID] != c.threshold {
+			return Finding{}, false
+		}
+		return findingFor(ev, fmt.Sprintf("task %s failed %d times in a row", ev.TaskID, c.threshold)), true
+	case cron.EventTaskRemoved, cron.EventTaskExpired:
+		delete(c.counts, ev.TaskID)
+	}
+	return Finding{}, false
+}
+
+// NoSuccessWithin 在任务超过 d 未成功执行时命中（由周期检查发现）；之后直到再次成功前不重复命中。
+// 起算点为任务注册时间或开始监听的时间。
+func NoSuccessWithin(d time.Duration) Condition {
+	return &noSuccessWithin{within: d, tasks: make(map[string]*successState)}
+}
+
+type successState struct {
+	labels      map[string]string
+	lastSuccess time.Time
+	reported    bool
+}
+
+type noSuccessWithin struct {
+	within time.Duration
+	mu     sync.Mutex
+	tasks  map[string]*successState
+}
+
+func (c *noSuccessWithin) seed(taskID string, labels map[string]string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.tasks[taskID]; !ok {
+		c.tasks[taskID] = &successState{labels: maps.Clone(labels), lastSuccess: now}
+	}
+}
+
+func (c *noSuccessWithin) Observe(ev cron.Event) (Finding, bool) {
+	if ev.TaskID == "" {
+		return Finding{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch ev.Type {
+	case cron.EventTaskRemoved, cron.EventTaskExpired:
+		delete(c.tasks, ev.TaskID)
+		return Finding{}, false
+	}
+
+	state, ok := c.tasks[ev.TaskID]
+	if !ok {
+		state = &successState{lastSuccess: ev.Time}
+		c.tasks[ev.TaskID] = state
+	}
+	if ev.Labels != nil {
+		state.labels = maps.Clone(ev.Labels)
+	}
+	if ev.Type == cron.EventTaskFinished && ev.Success {
+		state.lastSuccess = ev.Time
+		state.reported = false
+	}
+	return Finding{}, false
+}
+
+func (c *noSuccessWithin) Sweep(now time.Time) []Finding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var findings []Finding
+	for taskID, state := range c.tasks {
+		if state.reported || now.Sub(state.lastSuccess) <= c.within {
+			continue
+		}
+		state.reported = true
+		findings = append(findings, Finding{
+			TaskID:  taskID,
+			Labels:  maps.Clone(state.labels),
+			Message: fmt.Sprintf("task %s has not succeeded for %v (expected within %v)", taskID, now.Sub(state.lastSuccess).Truncate(time.Second), c.within),
+		})
+	}
+	slices.SortFunc(findings, func(a, b Finding) int { return strings.Compare(a.TaskID, b.TaskID) })
+	return findings
+}
+
+func findingFor(ev cron.Event, message string) Finding {
+	return Finding{TaskID: ev.TaskID, Labels: maps.Clone(ev.Labels), Message: message, Event: ev}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/darkit/cron"
+)
+
+// TestConsecutiveFailures 测试达到阈值时命中一次，成功后重新计数
+func TestConsecutiveFailures(t *testing.T) {
+	cond := ConsecutiveFailures(2)
+	fail := cron.Event{Type: cron.EventTaskFinished, TaskID: "a"}
+	ok := cron.Event{Type: cron.EventTaskFinished, TaskID: "a", Success: true}
+
+	var hits []bool
+	for _, ev := range []cron.Event{fail, fail, fail, ok, fail, fail} {
+		_, hit := cond.Observe(ev)
+		hits = append(hits, hit)
+	}
+	want := []bool{false, true, false, false, false, true}
+	for i := range want {
+		if hits[i] != want[i] {
+			t.Fatalf("hits = %v; want %v", hits, want)
+		}
+	}
+	if _, hit := cond.Observe(cron.Event{Type: cron.EventTaskFinished, TaskID: "b"}); hit {
+		t.Fatal("failures should be counted per task")
+	}
+}
+
+// TestNoSuccessWithin 测试超过期限未成功时由周期检查命中，成功后重新起算
+func TestNoSuccessWithin(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cond := NoSuccessWithin(time.Hour)
+	sweeper := cond.(Sweeper)
+	cond.(seeder).seed("seeded", map[string]string{"team": "ops"}, base)
+	cond.Observe(cron.Event{Type: cron.EventTaskAdded, TaskID: "added", Time: base.Add(30 * time.Minute)})
+
+	if findings := sweeper.Sweep(base.Add(time.Hour)); len(findings) != 0 {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+	findings := sweeper.Sweep(base.Add(61 * time.Minute))
+	if len(findings) != 1 || findings[0].TaskID != "seeded" || findings[0].Labels["team"] != "ops" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+	if again := sweeper.Sweep(base.Add(2 * time.Hour)); len(again) != 1 || again[0].TaskID != "added" {
+		t.Fatalf("already reported task should not repeat: %+v", again)
+	}
+
+	cond.Observe(cron.Event{Type: cron.EventTaskFinished, TaskID: "seeded", Success: true, Time: base.Add(2 * time.Hour)})
+	cond.Observe(cron.Event{Type: cron.EventTaskRemoved, TaskID: "added"})
+	findings = sweeper.Sweep(base.Add(4 * time.Hour))
+	if len(findings) != 1 || findings[0].TaskID != "seeded" {
+		t.Fatalf("expected re-report after success window lapsed: %+v", findings)
+	}
+}
+
+// TestOnEventAndAutoPaused 测试按事件类型命中的条件
+func TestOnEventAndAutoPaused(t *testing.T) {
+	if _, hit := OnEvent(cron.EventTaskSkipped).Observe(cron.Event{Type: cron.EventTaskSkipped, TaskID: "a"}); !hit {
+		t.Fatal("OnEvent should match listed types")
+	}
+	if _, hit := OnEvent(cron.EventTaskSkipped).Observe(cron.Event{Type: cron.EventTaskStarted}); hit {
+		t.Fatal("OnEvent should ignore other types")
+	}
+	finding, hit := AutoPaused().Observe(cron.Event{Type: cron.EventTaskAutoPaused, TaskID: "a", PauseUntil: time.Now()})
+	if !hit || finding.TaskID != "a" || finding.Event.Type != cron.EventTaskAutoPaused {
+		t.Fatalf("unexpected finding: %+v, %v", finding, hit)
+	}
+}
@@ -0,0 +1,381 @@
+// Package notify 基于调度器事件流发送失败告警。
+//
+// 每条 Rule 由过滤条件、告警条件与若干 Notifier 组成，Dispatcher 订阅 Cron 的事件流并逐条评估，
+// 同一规则下的同一任务在节流窗口内只发送一次告警，被抑制的次数随下一条告警一并报告。
+// 告警进入有界的发送队列，由独立的发送协程交给 Notifier，发送缓慢不会阻塞事件循环：
+//
+//	d := notify.NewDispatcher(c, []notify.Rule{{
+//		Name:      "repeated-failures",
+//		Condition: notify.ConsecutiveFailures(3),
+//		Notifiers: []notify.Notifier{notify.NewSlack(webhookURL)},
+//	}})
+//	defer d.Close()
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/darkit/cron"
+)
+
+const (
+	// DefaultThrottle 规则未设置 Throttle 时，同一任务两次告警的最小间隔
+	DefaultThrottle = 10 * time.Minute
+	// DefaultSendTimeout 单次发送的默认超时
+	DefaultSendTimeout = 10 * time.Second
+	// DefaultSweepInterval 周期性条件（如 NoSuccessWithin）的默认检查间隔
+	DefaultSweepInterval = 30 * time.Second
+	// DefaultBuffer 事件订阅的默认缓冲区大小
+	DefaultBuffer = 256
+	// DefaultWorkers 默认的发送协程数
+	DefaultWorkers = 4
+	// DefaultQueueSize 发送队列的默认容量，每个 Notifier 的每条告警占一项
+	DefaultQueueSize = 256
+)
+
+// ErrQueueFull 发送队列已满，告警被丢弃，通过 WithErrorHandler 报告
+var ErrQueueFull = errors.New("notify: delivery queue is full")
+
+// Alert 一条告警
+type Alert struct {
+	Rule        string            `json:"rule"`
+	TaskID      string            `json:"taskId"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Message     string            `json:"message"`
+	EventType   cron.EventType    `json:"eventType,omitempty"`   // 触发告警的事件类型，周期检查命中时为空
+	ExecutionID string            `json:"executionId,omitempty"` // 触发告警的执行 ID
+	Error       string            `json:"error,omitempty"`       // 最近一次错误信息
+	Time        time.Time         `json:"time"`
+	Suppressed  int               `json:"suppressed,omitempty"` // 上次告警后被节流抑制的次数
+}
+
+// Title 返回告警标题，用于邮件主题与聊天消息首行
+func (a Alert) Title() string {
+	return fmt.Sprintf("[cron] %s: task %s", a.Rule, a.TaskID)
+}
+
+// Text 返回纯文本告警正文
+func (a Alert) Text() string {
+	text := a.Message
+	if a.Error != "" {
+		text += "\nerror: " + a.Error
+	}
+	if a.ExecutionID != "" {
+		text += "\nexecution: " + a.ExecutionID
+	}
+	if a.Suppressed > 0 {
+		text += fmt.Sprintf("\n(%d similar alerts suppressed)", a.Suppressed)
+	}
+	return text
+}
+
+// Notifier 告警发送接口
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NotifierFunc 函数适配器
+type NotifierFunc func(ctx context.Context, alert Alert) error
+
+// Notify 调用函数本身
+func (f NotifierFunc) Notify(ctx context.Context, alert Alert) error {
+	return f(ctx, alert)
+}
+
+// Rule 告警规则。Condition 带有按任务维护的状态，不能在多条规则之间共享
+type Rule struct {
+	Name      string
+	Filter    cron.EventFilter // 参与评估的事件范围，零值表示全部
+	Condition Condition
+	Notifiers []Notifier
+	Throttle  time.Duration // 同一任务两次告警的最小间隔，0 使用 DefaultThrottle，负数表示不节流
+}
+
+// Option Dispatcher 配置选项
+type Option func(*Dispatcher)
+
+// WithSendTimeout 设置单次发送的超时
+func WithSendTimeout(timeout time.Duration) Option {
+	return func(d *Dispatcher) {
+		if timeout > 0 {
+			d.sendTimeout = timeout
+		}
+	}
+}
+
+// WithSweepInterval 设置周期性条件的检查间隔
+func WithSweepInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		if interval > 0 {
+			d.sweepInterval = interval
+		}
+	}
+}
+
+// WithBuffer 设置事件订阅的缓冲区大小
+func WithBuffer(size int) Option {
+	return func(d *Dispatcher) {
+		d.buffer = size
+	}
+}
+
+// WithWorkers 设置发送协程数，多个 Notifier 或多条告警可并行发送
+func WithWorkers(n int) Option {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.workers = n
+		}
+	}
+}
+
+// WithQueueSize 设置发送队列容量，队列满时丢弃告警并以 ErrQueueFull 调用错误回调
+func WithQueueSize(size int) Option {
+	return func(d *Dispatcher) {
+		if size > 0 {
+			d.queueSize = size
+		}
+	}
+}
+
+// WithErrorHandler 设置发送失败回调，默认忽略。回调在发送协程中调用，可能并发执行
+func WithErrorHandler(handler func(alert Alert, err error)) Option {
+	return func(d *Dispatcher) {
+		d.onError = handler
+	}
+}
+
+// delivery 发送队列中的一项
+type delivery struct {
+	alert    Alert
+	notifier Notifier
+}
+
+// throttleKey 节流维度：规则 + 任务
+type throttleKey struct {
+	rule   int
+	taskID string
+}
+
+// throttleState 节流状态
+type throttleState struct {
+	lastSent   time.Time
+	suppressed int
+}
+
+// Dispatcher 订阅事件流并按规则发送告警
+type Dispatcher struct {
+	rules         []Rule
+	sendTimeout   time.Duration
+	sweepInterval time.Duration
+	buffer        int
+	workers       int
+	queueSize     int
+	onError       func(alert Alert, err error)
+	now           func() time.Time
+
+	queue     chan delivery
+	workersWG sync.WaitGroup
+
+	mu       sync.Mutex
+	throttle map[throttleKey]*throttleState
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewDispatcher 创建告警分发器并立即开始订阅 c 的事件流，Close 停止分发
+func NewDispatcher(c *cron.Cron, rules []Rule, opts ...Option) *Dispatcher {
+	d := newDispatcher(rules, opts...)
+
+	// 先订阅再登记已有任务：两者之间新增的任务既会被登记，其事件也不会丢失；
+	// 登记是幂等的，事件循环在登记完成后才开始处理缓冲的事件
+	events, unsubscribe := c.Subscribe(cron.EventFilter{}, d.buffer)
+	now := d.now()
+	for _, info := range c.GetAllTasks() {
+		d.seed(info.ID, info.Labels, now)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.startWorkers(ctx)
+	go func() {
+		defer close(d.done)
+		defer unsubscribe()
+		d.run(ctx, events)
+	}()
+	return d
+}
+
+func newDispatcher(rules []Rule, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		rules:         rules,
+		sendTimeout:   DefaultSendTimeout,
+		sweepInterval: DefaultSweepInterval,
+		buffer:        DefaultBuffer,
+		workers:       DefaultWorkers,
+		queueSize:     DefaultQueueSize,
+		now:           time.Now,
+		throttle:      make(map[throttleKey]*throttleState),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.queue = make(chan delivery, d.queueSize)
+	return d
+}
+
+// startWorkers 启动发送协程，ctx 取消后退出，队列中尚未发送的告警被丢弃
+func (d *Dispatcher) startWorkers(ctx context.Context) {
+	for range d.workers {
+		d.workersWG.Add(1)
+		go func() {
+			defer d.workersWG.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-d.queue:
+					d.deliver(ctx, job)
+				}
+			}
+		}()
+	}
+}
+
+// Close 停止订阅，取消正在进行的发送并等待事件循环与发送协程退出
+func (d *Dispatcher) Close() {
+	d.once.Do(func() {
+		if d.cancel != nil {
+			d.cancel()
+			<-d.done
+			d.workersWG.Wait()
+		}
+	})
+}
+
+// run 事件循环：逐个评估事件，并按间隔执行周期检查
+func (d *Dispatcher) run(ctx context.Context, events <-chan cron.Event) {
+	ticker := time.NewTicker(d.sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			d.handle(ev)
+		case <-ticker.C:
+			d.sweep(d.now())
+		}
+	}
+}
+
+// seed 为需要基线的条件登记任务
+func (d *Dispatcher) seed(taskID string, labels map[string]string, now time.Time) {
+	for _, rule := range d.rules {
+		if s, ok := rule.Condition.(seeder); ok && rule.Filter.Match(cron.Event{TaskID: taskID, Labels: labels}) {
+			s.seed(taskID, labels, now)
+		}
+	}
+}
+
+// handle 按规则评估一个事件
+func (d *Dispatcher) handle(ev cron.Event) {
+	for i, rule := range d.rules {
+		if rule.Condition == nil || !rule.Filter.Match(ev) {
+			continue
+		}
+		if finding, ok := rule.Condition.Observe(ev); ok {
+			d.fire(i, finding)
+		}
+	}
+	// 任务移除后清理其节流状态，避免任务频繁增删时节流表无限增长
+	if ev.Type == cron.EventTaskRemoved || ev.Type == cron.EventTaskExpired {
+		d.mu.Lock()
+		for key := range d.throttle {
+			if key.taskID == ev.TaskID {
+				delete(d.throttle, key)
+			}
+		}
+		d.mu.Unlock()
+	}
+}
+
+// sweep 执行周期性条件检查
+func (d *Dispatcher) sweep(now time.Time) {
+	for i, rule := range d.rules {
+		sweeper, ok := rule.Condition.(Sweeper)
+		if !ok {
+			continue
+		}
+		for _, finding := range sweeper.Sweep(now) {
+			d.fire(i, finding)
+		}
+	}
+}
+
+// fire 经节流后将命中结果放入发送队列，规则的每个 Notifier 各占一项
+func (d *Dispatcher) fire(ruleIndex int, finding Finding) {
+	rule := d.rules[ruleIndex]
+	now := d.now()
+	throttle := rule.Throttle
+	if throttle == 0 {
+		throttle = DefaultThrottle
+	}
+
+	d.mu.Lock()
+	key := throttleKey{rule: ruleIndex, taskID: finding.TaskID}
+	state := d.throttle[key]
+	if state == nil {
+		state = &throttleState{}
+		d.throttle[key] = state
+	}
+	if throttle > 0 && !state.lastSent.IsZero() && now.Sub(state.lastSent) < throttle {
+		state.suppressed++
+		d.mu.Unlock()
+		return
+	}
+	suppressed := state.suppressed
+	state.lastSent = now
+	state.suppressed = 0
+	d.mu.Unlock()
+
+	alert := Alert{
+		Rule:        rule.Name,
+		TaskID:      finding.TaskID,
+		Labels:      maps.Clone(finding.Labels),
+		Message:     finding.Message,
+		EventType:   finding.Event.Type,
+		ExecutionID: finding.Event.ExecutionID,
+		Error:       finding.Event.Error,
+		Time:        now,
+		Suppressed:  suppressed,
+	}
+	for _, notifier := range rule.Notifiers {
+		select {
+		case d.queue <- delivery{alert: alert, notifier: notifier}:
+		default:
+			if d.onError != nil {
+				d.onError(alert, ErrQueueFull)
+			}
+		}
+	}
+}
+
+// deliver 在发送协程中将告警交给 Notifier
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	sendCtx, cancel := context.WithTimeout(ctx, d.sendTimeout)
+	err := job.notifier.Notify(sendCtx, job.alert)
+	cancel()
+	if err != nil && d.onError != nil {
+		d.onError(job.alert, err)
+	}
+}
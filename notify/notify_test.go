@@ -0,0 +1,247 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darkit/cron"
+)
+
+// recorder 记录收到的告警
+type recorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (r *recorder) Notify(ctx context.Context, alert Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *recorder) snapshot() []Alert {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Alert(nil), r.alerts...)
+}
+
+// failingJob 总是返回错误
+type failingJob struct{}
+
+func (failingJob) Name() string                  { return "failing" }
+func (failingJob) Run(ctx context.Context) error { return errors.New("disk full") }
+
+// TestDispatcherWithCron 测试连续失败与熔断暂停规则从事件流触发告警
+func TestDispatcherWithCron(t *testing.T) {
+	c := cron.New(cron.WithLogger(&cron.NoOpLogger{}))
+	defer c.Close()
+	err := c.ScheduleJob("backup", "0 0 1 1 *", failingJob{}, cron.JobOptions{
+		FailThreshold: 3,
+		PauseDuration: time.Hour,
+		Labels:        map[string]string{"team": "ops"},
+	})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	failures, paused := &recorder{}, &recorder{}
+	d := NewDispatcher(c, []Rule{
+		{Name: "repeated-failures", Condition: ConsecutiveFailures(2), Notifiers: []Notifier{failures}},
+		{Name: "circuit-breaker", Condition: AutoPaused(), Notifiers: []Notifier{paused}},
+	})
+	defer d.Close()
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	for range 3 {
+		if err := c.RunNow("backup"); err != nil {
+			t.Fatalf("run now failed: %v", err)
+		}
+	}
+
+	waitFor(t, func() bool { return len(failures.snapshot()) == 1 && len(paused.snapshot()) == 1 })
+	alert := failures.snapshot()[0]
+	if alert.Rule != "repeated-failures" || alert.TaskID != "backup" || alert.Error != "disk full" ||
+		alert.Labels["team"] != "ops" || alert.ExecutionID == "" || alert.EventType != cron.EventTaskFinished {
+		t.Fatalf("unexpected alert: %+v", alert)
+	}
+	if got := paused.snapshot()[0]; got.EventType != cron.EventTaskAutoPaused {
+		t.Fatalf("unexpected pause alert: %+v", got)
+	}
+}
+
+// TestDispatcherThrottle 测试节流窗口内同一任务只告警一次，并在下一条告警中报告被抑制的次数
+func TestDispatcherThrottle(t *testing.T) {
+	rec := &recorder{}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := newDispatcher([]Rule{{
+		Name:      "any-failure",
+		Condition: ConsecutiveFailures(1),
+		Notifiers: []Notifier{rec},
+		Throttle:  time.Minute,
+	}}, WithWorkers(1))
+	d.now = func() time.Time { return now }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.startWorkers(ctx)
+
+	fail := func(taskID string) {
+		d.handle(cron.Event{Type: cron.EventTaskFinished, TaskID: taskID})
+		d.handle(cron.Event{Type: cron.EventTaskFinished, TaskID: taskID, Success: true})
+	}
+	fail("a")
+	fail("a")
+	fail("a")
+	fail("b") // 不同任务独立节流
+	now = now.Add(2 * time.Minute)
+	fail("a")
+
+	waitFor(t, func() bool { return len(rec.snapshot()) >= 3 })
+	alerts := rec.snapshot()
+	if len(alerts) != 3 {
+		t.Fatalf("expected 3 alerts, got %d: %+v", len(alerts), alerts)
+	}
+	if alerts[0].TaskID != "a" || alerts[1].TaskID != "b" || alerts[2].TaskID != "a" {
+		t.Fatalf("unexpected alert order: %+v", alerts)
+	}
+	if alerts[2].Suppressed != 2 {
+		t.Fatalf("suppressed = %d; want 2", alerts[2].Suppressed)
+	}
+}
+
+// TestDispatcherThrottleCleanup 测试任务移除或过期后清理节流状态
+func TestDispatcherThrottleCleanup(t *testing.T) {
+	rec := &recorder{}
+	d := newDispatcher([]Rule{{
+		Name:      "any-failure",
+		Condition: ConsecutiveFailures(1),
+		Notifiers: []Notifier{rec},
+		Throttle:  time.Hour,
+	}}, WithWorkers(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.startWorkers(ctx)
+
+	for _, taskID := range []string{"a", "b"} {
+		d.handle(cron.Event{Type: cron.EventTaskFinished, TaskID: taskID})
+	}
+	d.handle(cron.Event{Type: cron.EventTaskRemoved, TaskID: "a"})
+	d.handle(cron.Event{Type: cron.EventTaskExpired, TaskID: "b"})
+	d.mu.Lock()
+	remaining := len(d.throttle)
+	d.mu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("throttle entries = %d; want 0", remaining)
+	}
+
+	// 同名任务重新注册后不受旧节流状态影响
+	d.handle(cron.Event{Type: cron.EventTaskFinished, TaskID: "a"})
+	waitFor(t, func() bool { return len(rec.snapshot()) >= 3 })
+}
+
+// TestDispatcherFilterAndErrors 测试规则过滤条件与发送失败回调
+func TestDispatcherFilterAndErrors(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		failed []Alert
+	)
+	snapshot := func() []Alert {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Alert(nil), failed...)
+	}
+	d := newDispatcher([]Rule{{
+		Name:      "ops-only",
+		Filter:    cron.EventFilter{Labels: map[string]string{"team": "ops"}},
+		Condition: OnEvent(cron.EventTaskSkipped),
+		Notifiers: []Notifier{NotifierFunc(func(ctx context.Context, alert Alert) error {
+			return errors.New("unreachable")
+		})},
+	}}, WithErrorHandler(func(alert Alert, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = append(failed, alert)
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.startWorkers(ctx)
+
+	d.handle(cron.Event{Type: cron.EventTaskSkipped, TaskID: "dev", Labels: map[string]string{"team": "dev"}})
+	d.handle(cron.Event{Type: cron.EventTaskSkipped, TaskID: "ops", Labels: map[string]string{"team": "ops"}})
+	waitFor(t, func() bool { return len(snapshot()) > 0 })
+	if got := snapshot(); len(got) != 1 || got[0].TaskID != "ops" {
+		t.Fatalf("unexpected failed alerts: %+v", got)
+	}
+}
+
+// TestDispatcherSlowNotifier 测试发送缓慢时事件循环不被阻塞，队列满时以 ErrQueueFull 报告丢弃
+func TestDispatcherSlowNotifier(t *testing.T) {
+	release := make(chan struct{})
+	slow := NotifierFunc(func(ctx context.Context, alert Alert) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	var dropped sync.Map
+	d := newDispatcher([]Rule{{
+		Name:      "any-skip",
+		Condition: OnEvent(cron.EventTaskSkipped),
+		Notifiers: []Notifier{slow},
+		Throttle:  -1,
+	}}, WithWorkers(1), WithQueueSize(2), WithErrorHandler(func(alert Alert, err error) {
+		if errors.Is(err, ErrQueueFull) {
+			dropped.Store(alert.TaskID, true)
+		}
+	}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.startWorkers(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			d.handle(cron.Event{Type: cron.EventTaskSkipped, TaskID: id})
+			time.Sleep(5 * time.Millisecond) // 让发送协程先取走第一条
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("slow notifier should not block event handling")
+	}
+	if _, ok := dropped.Load("e"); !ok {
+		t.Fatal("alerts beyond the queue capacity should be reported as dropped")
+	}
+	close(release)
+}
+
+// TestAlertText 测试告警标题与正文
+func TestAlertText(t *testing.T) {
+	alert := Alert{Rule: "r", TaskID: "t", Message: "boom happened", Error: "boom", ExecutionID: "t_1", Suppressed: 2}
+	if alert.Title() != "[cron] r: task t" {
+		t.Fatalf("unexpected title %q", alert.Title())
+	}
+	want := "boom happened\nerror: boom\nexecution: t_1\n(2 similar alerts suppressed)"
+	if alert.Text() != want {
+		t.Fatalf("unexpected text %q", alert.Text())
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP 以纯文本邮件发送告警。服务器支持 STARTTLS 时自动升级连接
+type SMTP struct {
+	Addr      string      // 服务器地址，host:port
+	From      string      // 发件人
+	To        []string    // 收件人
+	Auth      smtp.Auth   // 鉴权方式，可选
+	TLSConfig *tls.Config // STARTTLS 配置，nil 时按 Addr 的主机名校验证书
+}
+
+// NewSMTP 创建邮件通知器
+func NewSMTP(addr, from string, to ...string) *SMTP {
+	return &SMTP{Addr: addr, From: from, To: to}
+}
+
+// Notify 发送告警邮件，ctx 的截止时间作用于整个 SMTP 会话
+func (s *SMTP) Notify(ctx context.Context, alert Alert) error {
+	if len(s.To) == 0 {
+		return errors.New("smtp: no recipients")
+	}
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid address %q: %w", s.Addr, err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: dial: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		config := s.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := client.StartTLS(config); err != nil {
+			return fmt.Errorf("smtp: starttls: %w", err)
+		}
+	}
+	if s.Auth != nil {
+		if err := client.Auth(s.Auth); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return fmt.Errorf("smtp: mail from: %w", err)
+	}
+	for _, rcpt := range s.To {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp: rcpt to %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+	if _, err := w.Write(s.message(alert)); err != nil {
+		return fmt.Errorf("smtp: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: send message: %w", err)
+	}
+	return client.Quit()
+}
+
+// message 构造 RFC 5322 邮件内容
+func (s *SMTP) message(alert Alert) []byte {
+	var b strings.Builder
+	header := func(key, value string) {
+		b.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", s.From)
+	header("To", strings.Join(s.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", sanitizeHeader(alert.Title())))
+	header("Date", alert.Time.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(alert.Text(), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// sanitizeHeader 去除换行，防止头部注入
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer 最小化的本地 SMTP 服务器，记录收件人与邮件内容
+type fakeSMTPServer struct {
+	addr string
+	rcpt chan []string
+	data chan string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	srv := &fakeSMTPServer{addr: ln.Addr().String(), rcpt: make(chan []string, 1), data: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		var rcpts []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM"):
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO"):
+				rcpts = append(rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var body strings.Builder
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					body.WriteString(dataLine)
+				}
+				srv.rcpt <- rcpts
+				srv.data <- body.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+	return srv
+}
+
+// TestSMTPNotify 测试通过本地 SMTP 服务器发送告警邮件
+func TestSMTPNotify(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	mailer := NewSMTP(srv.addr, "cron@example.com", "ops@example.com", "oncall@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	alert := testAlert
+	alert.Rule = "evil\r\nBcc: attacker@example.com"
+	if err := mailer.Notify(ctx, alert); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	rcpts := <-srv.rcpt
+	if len(rcpts) != 2 || rcpts[0] != "<ops@example.com>" || rcpts[1] != "<oncall@example.com>" {
+		t.Fatalf("unexpected recipients: %v", rcpts)
+	}
+	data := <-srv.data
+	for _, want := range []string{
+		"From: cron@example.com\r\n",
+		"To: ops@example.com, oncall@example.com\r\n",
+		"Subject: [cron] evil  Bcc: attacker@example.com: task backup\r\n",
+		"\r\ntask backup failed 3 times in a row\r\nerror: disk full\r\n",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("missing %q in message:\n%s", want, data)
+		}
+	}
+	if strings.Contains(data, "\r\nBcc:") {
+		t.Fatal("header injection should be neutralised")
+	}
+}
+
+// TestSMTPSubjectEncoding 测试非 ASCII 主题按 RFC 2047 编码
+func TestSMTPSubjectEncoding(t *testing.T) {
+	alert := testAlert
+	alert.Rule = "连续失败"
+	alert.TaskID = "备份任务"
+	message := string(NewSMTP("127.0.0.1:25", "cron@example.com", "ops@example.com").message(alert))
+
+	_, rest, _ := strings.Cut(message, "Subject: ")
+	subject, _, _ := strings.Cut(rest, "\r\n")
+	if !strings.HasPrefix(subject, "=?utf-8?q?") {
+		t.Fatalf("subject should be encoded-word, got %q", subject)
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(subject)
+	if err != nil || decoded != alert.Title() {
+		t.Fatalf("decoded subject = %q (%v), want %q", decoded, err, alert.Title())
+	}
+}
+
+// TestSMTPNotifyErrors 测试缺少收件人与连接失败时返回错误
+func TestSMTPNotifyErrors(t *testing.T) {
+	if err := NewSMTP("127.0.0.1:25", "cron@example.com").Notify(context.Background(), testAlert); err == nil {
+		t.Fatal("expected error without recipients")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	if err := NewSMTP(addr, "cron@example.com", "ops@example.com").Notify(context.Background(), testAlert); err == nil {
+		t.Fatal("expected dial error")
+	}
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Webhook 以 JSON POST 发送告警，请求体为 Alert
+type Webhook struct {
+	URL     string
+	Headers map[string]string // 额外请求头，如鉴权令牌
+	Client  *http.Client      // nil 时使用 http.DefaultClient
+}
+
+// NewWebhook 创建通用 Webhook 通知器
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url}
+}
+
+// Notify 发送告警
+func (w *Webhook) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, w.Client, w.URL, w.Headers, alert)
+}
+
+// Slack 以 Slack Incoming Webhook 兼容格式发送告警（Mattermost、Rocket.Chat 等同样适用）
+type Slack struct {
+	WebhookURL string
+	Channel    string       // 覆盖默认频道，可选
+	Username   string       // 覆盖显示名称，可选
+	Client     *http.Client // nil 时使用 http.DefaultClient
+}
+
+// NewSlack 创建 Slack 通知器
+func NewSlack(webhookURL string) *Slack {
+	return &Slack{WebhookURL: webhookURL}
+}
+
+// slackPayload Slack Incoming Webhook 请求体
+type slackPayload struct {
+	Text        string            `json:"text"`
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Attachments []slackAttachment `json:"attachments,omitempty"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Text   string       `json:"text"`
+	Fields []slackField `json:"fields,omitempty"`
+	Ts     int64        `json:"ts"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Notify 发送告警
+func (s *Slack) Notify(ctx context.Context, alert Alert) error {
+	fields := []slackField{{Title: "Task", Value: alert.TaskID, Short: true}, {Title: "Rule", Value: alert.Rule, Short: true}}
+	if alert.Error != "" {
+		fields = append(fields, slackField{Title: "Error", Value: alert.Error})
+	}
+	if alert.ExecutionID != "" {
+		fields = append(fields, slackField{Title: "Execution", Value: alert.ExecutionID, Short: true})
+	}
+	if alert.Suppressed > 0 {
+		fields = append(fields, slackField{Title: "Suppressed", Value: fmt.Sprint(alert.Suppressed), Short: true})
+	}
+	keys := make([]string, 0, len(alert.Labels))
+	for key := range alert.Labels {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fields = append(fields, slackField{Title: key, Value: alert.Labels[key], Short: true})
+	}
+
+	payload := slackPayload{
+		Text:     alert.Title(),
+		Channel:  s.Channel,
+		Username: s.Username,
+		Attachments: []slackAttachment{{
+			Color:  "danger",
+			Text:   alert.Message,
+			Fields: fields,
+			Ts:     alert.Time.Unix(),
+		}},
+	}
+	return postJSON(ctx, s.Client, s.WebhookURL, nil, payload)
+}
+
+// postJSON 发送 JSON POST 请求，非 2xx 响应视为失败
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("send alert: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureServer 返回记录请求体的测试服务器
+func captureServer(t *testing.T, status int, bodies *[]map[string]any, headers *http.Header) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body failed: %v", err)
+		}
+		*bodies = append(*bodies, body)
+		if headers != nil {
+			*headers = r.Header.Clone()
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("nope"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+var testAlert = Alert{
+	Rule:        "repeated-failures",
+	TaskID:      "backup",
+	Labels:      map[string]string{"team": "ops"},
+	Message:     "task backup failed 3 times in a row",
+	Error:       "disk full",
+	ExecutionID: "backup_1",
+	Time:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	Suppressed:  2,
+}
+
+// TestWebhookNotify 测试通用 Webhook 以 JSON 发送告警并携带自定义请求头
+func TestWebhookNotify(t *testing.T) {
+	var (
+		bodies  []map[string]any
+		headers http.Header
+	)
+	srv := captureServer(t, http.StatusOK, &bodies, &headers)
+
+	hook := NewWebhook(srv.URL)
+	hook.Headers = map[string]string{"Authorization": "Bearer token"}
+	if err := hook.Notify(context.Background(), testAlert); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(bodies))
+	}
+	body := bodies[0]
+	if body["rule"] != "repeated-failures" || body["taskId"] != "backup" || body["error"] != "disk full" ||
+		body["suppressed"] != float64(2) {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if headers.Get("Authorization") != "Bearer token" || headers.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+}
+
+// TestWebhookNotifyStatusError 测试非 2xx 响应返回错误
+func TestWebhookNotifyStatusError(t *testing.T) {
+	var bodies []map[string]any
+	srv := captureServer(t, http.StatusBadGateway, &bodies, nil)
+	err := NewWebhook(srv.URL).Notify(context.Background(), testAlert)
+	if err == nil || !strings.Contains(err.Error(), "502") || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
+
+// TestSlackNotify 测试 Slack 兼容负载
+func TestSlackNotify(t *testing.T) {
+	var bodies []map[string]any
+	srv := captureServer(t, http.StatusOK, &bodies, nil)
+
+	slack := NewSlack(srv.URL)
+	slack.Channel = "#alerts"
+	if err := slack.Notify(context.Background(), testAlert); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+	body := bodies[0]
+	if body["text"] != "[cron] repeated-failures: task backup" || body["channel"] != "#alerts" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	attachment := body["attachments"].([]any)[0].(map[string]any)
+	if attachment["color"] != "danger" || attachment["text"] != testAlert.Message {
+		t.Fatalf("unexpected attachment: %v", attachment)
+	}
+	titles := map[string]string{}
+	for _, field := range attachment["fields"].([]any) {
+		f := field.(map[string]any)
+		titles[f["title"].(string)] = f["value"].(string)
+	}
+	if titles["Error"] != "disk full" || titles["team"] != "ops" || titles["Suppressed"] != "2" {
+		t.Fatalf("unexpected fields: %v", titles)
+	}
+}
@@ -0,0 +1,67 @@
+package cron
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// DefaultOverdueCheckInterval 成功 SLA 的默认检查间隔
+const DefaultOverdueCheckInterval = time.Second
+
+// watchOverdue 周期检查设置了 ExpectSuccessWithin 的任务（含暂停中的任务），
+// 任务由未超期变为超期时发出 TaskOverdue 事件；再次成功后清除超期标记，之后可再次触发。
+// 每次检查同时找出停滞的调度循环，完成后刷新心跳，供 Health 判断调度是否停滞
+func (s *scheduler) watchOverdue(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.overdueCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			stalled := s.stalledTasks(now)
+			s.stalled.Store(&stalled)
+			if s.monitor != nil {
+				for _, task := range s.monitor.checkOverdue(now) {
+					attrs := []slog.Attr{slog.String(LogKeyTaskID, task.id), slog.Duration("expect_success_within", task.within)}
+					if !task.lastSuccess.IsZero() {
+						attrs = append(attrs, slog.Time("last_success", task.lastSuccess))
+					}
+					logAttrs(ctx, s.logger, slog.LevelWarn, "task has not succeeded within its SLA", attrs...)
+					s.emit(Event{Type: EventTaskOverdue, TaskID: task.id, Labels: task.labels, LastSuccess: task.lastSuccess})
+				}
+			}
+			s.heartbeat.Store(now.UnixNano())
+		}
+	}
+}
+
+// stalledTasks 返回已到期超过 heartbeatStaleFactor 个检查间隔、调度循环仍未处理的任务。
+// 同步执行任务期间不计入；只读取原子字段，任务锁死锁时同样可以发现
+func (s *scheduler) stalledTasks(now time.Time) []string {
+	staleAfter := int64(heartbeatStaleFactor * s.overdueCheckInterval)
+	current := monoNanos(now)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var stalled []string
+	for id, runner := range s.tasks {
+		if due := runner.dueAt.Load(); due != 0 && current-due > staleAfter {
+			stalled = append(stalled, id)
+		}
+	}
+	slices.Sort(stalled)
+	return stalled
+}
+
+// monoEpoch 单调时钟基准
+var monoEpoch = time.Now()
+
+// monoNanos 返回 t 相对 monoEpoch 的单调时钟纳秒数，系统时间调整与休眠不影响比较
+func monoNanos(t time.Time) int64 {
+	return int64(t.Sub(monoEpoch))
+}
@@ -0,0 +1,127 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// waitOverdueEvent 等待一个 TaskOverdue 事件
+func waitOverdueEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for overdue event")
+		return Event{}
+	}
+}
+
+// TestExpectSuccessWithin 测试超过 SLA 未成功时标记超期并发出事件，成功后清除并可再次触发
+func TestExpectSuccessWithin(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}), WithOverdueCheckInterval(20*time.Millisecond))
+	defer c.Close()
+
+	events, cancel := c.Subscribe(EventFilter{Types: []EventType{EventTaskOverdue}}, 8)
+	defer cancel()
+
+	opts := JobOptions{ExpectSuccessWithin: 150 * time.Millisecond, Labels: map[string]string{"team": "ops"}}
+	if err := c.Schedule("nightly", "0 0 1 1 *", func(ctx context.Context) {}, opts); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Schedule("no-sla", "0 0 1 1 *", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+
+	ev := waitOverdueEvent(t, events)
+	if ev.TaskID != "nightly" || !ev.LastSuccess.IsZero() || ev.Labels["team"] != "ops" {
+		t.Fatalf("unexpected overdue event: %+v", ev)
+	}
+	if stats, _ := c.GetStats("nightly"); !stats.Overdue || stats.ExpectSuccessWithin != opts.ExpectSuccessWithin {
+		t.Fatalf("stats should be overdue: %+v", stats)
+	}
+	if stats, _ := c.GetStats("no-sla"); stats.Overdue {
+		t.Fatal("task without SLA should never be overdue")
+	}
+
+	// 超期期间不重复发出事件
+	time.Sleep(60 * time.Millisecond)
+	if got := drain(events); len(got) != 0 {
+		t.Fatalf("overdue event should fire once per transition, got %+v", got)
+	}
+
+	if err := c.RunNow("nightly"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+	stats, _ := c.GetStats("nightly")
+	if stats.Overdue || stats.LastSuccess.IsZero() {
+		t.Fatalf("success should clear overdue flag: %+v", stats)
+	}
+
+	ev = waitOverdueEvent(t, events)
+	if ev.TaskID != "nightly" || !ev.LastSuccess.Equal(stats.LastSuccess) {
+		t.Fatalf("expected second overdue event with last success, got %+v", ev)
+	}
+}
+
+// TestExpectSuccessWithinValidation 测试负数 SLA 被拒绝，更新为 0 时清除超期标记
+func TestExpectSuccessWithinValidation(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	defer c.Close()
+
+	if err := c.Schedule("bad", "* * * * *", func(ctx context.Context) {}, JobOptions{ExpectSuccessWithin: -time.Second}); err == nil {
+		t.Fatal("negative expect success within should be rejected")
+	}
+
+	if err := c.Schedule("task", "0 0 1 1 *", func(ctx context.Context) {}, JobOptions{ExpectSuccessWithin: time.Millisecond}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	c.monitor.checkOverdue(time.Now().Add(time.Second))
+	if stats, _ := c.GetStats("task"); !stats.Overdue {
+		t.Fatal("task should be overdue")
+	}
+	if err := c.Update("task", "0 0 1 1 *", JobOptions{}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if stats, _ := c.GetStats("task"); stats.Overdue || stats.ExpectSuccessWithin != 0 {
+		t.Fatalf("clearing SLA should clear overdue flag: %+v", stats)
+	}
+}
+
+// TestCheckOverdueSLACount 测试按设置了 SLA 的任务数跳过检查，添加、更新与移除任务时计数保持正确
+func TestCheckOverdueSLACount(t *testing.T) {
+	m := newMonitor()
+	created := time.Now()
+	m.addTask("a", "* * * * *", created, nil, "")
+	m.addTask("b", "* * * * *", created, nil, "")
+	if m.slaTasks.Load() != 0 || m.checkOverdue(created.Add(time.Hour)) != nil {
+		t.Fatal("tasks without SLA should skip the check")
+	}
+
+	m.setExpectSuccessWithin("a", time.Minute)
+	m.setExpectSuccessWithin("a", 2*time.Minute)
+	m.setExpectSuccessWithin("b", time.Minute)
+	if n := m.slaTasks.Load(); n != 2 {
+		t.Fatalf("expected 2 SLA tasks, got %d", n)
+	}
+	if became := m.checkOverdue(created.Add(90 * time.Second)); len(became) != 1 || became[0].id != "b" {
+		t.Fatalf("expected only b to become overdue: %+v", became)
+	}
+	if became := m.checkOverdue(created.Add(90 * time.Second)); len(became) != 0 {
+		t.Fatalf("unchanged state should not report again: %+v", became)
+	}
+
+	m.setExpectSuccessWithin("b", 0)
+	m.removeTask("a")
+	m.addTask("a", "* * * * *", created, nil, "")
+	if n := m.slaTasks.Load(); n != 0 {
+		t.Fatalf("expected no SLA tasks, got %d", n)
+	}
+	if stats, _ := m.GetStats("b"); stats.Overdue {
+		t.Fatal("clearing SLA should clear overdue flag")
+	}
+}
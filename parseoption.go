@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/darkit/cron/internal/parser"
+)
+
+// ParseOption 表达式解析选项，决定接受哪些字段，可按位组合
+type ParseOption int
+
+const (
+	Second         = ParseOption(parser.Second)         // 秒字段，缺省为 0
+	SecondOptional = ParseOption(parser.SecondOptional) // 可选秒字段，缺省为 0
+	Minute         = ParseOption(parser.Minute)         // 分字段，缺省为 0
+	Hour           = ParseOption(parser.Hour)           // 时字段，缺省为 0
+	Dom            = ParseOption(parser.Dom)            // 日字段，缺省为 *
+	Month          = ParseOption(parser.Month)          // 月字段，缺省为 *
+	Dow            = ParseOption(parser.Dow)            // 周字段，缺省为 *
+	DowOptional    = ParseOption(parser.DowOptional)    // 可选周字段，缺省为 *
+	Descriptor     = ParseOption(parser.Descriptor)     // 允许 @daily、@every 等内置描述符
+)
+
+// WithParserOptions 设置表达式解析选项，替代默认的按字段数自动识别5段/6段格式。
+// 例如 Minute|Hour|Dom|Month|Dow 为严格5段且禁用内置描述符，
+// SecondOptional|Minute|Hour|Dom|Month|Dow|Descriptor 为秒可选。
+// SecondOptional 与 DowOptional 不能同时使用，否则添加任务时报错。
+func WithParserOptions(options ParseOption) Option {
+	return func(c *Cron) {
+		c.parserOptions = options
+	}
+}
+
+// WithDescriptor 注册自定义描述符，如 WithDescriptor("@businesshours", "0 9-17 * * 1-5")。
+// name 必须以 @ 开头且整体匹配；展开后的表达式按解析选项解析，不受 Descriptor 选项限制。
+// 名称或表达式无效时 New 会通过日志告警并忽略该描述符。
+func WithDescriptor(name, spec string) Option {
+	return func(c *Cron) {
+		name = strings.TrimSpace(name)
+		spec = strings.TrimSpace(spec)
+		if !strings.HasPrefix(name, "@") {
+			c.descriptorErrs = append(c.descriptorErrs, fmt.Errorf("descriptor %q must start with @", name))
+			return
+		}
+		if spec == "" {
+			c.descriptorErrs = append(c.descriptorErrs, fmt.Errorf("descriptor %q has an empty spec", name))
+			return
+		}
+		if c.descriptors == nil {
+			c.descriptors = make(map[string]string)
+		}
+		c.descriptors[name] = spec
+	}
+}
+
+// validateDescriptors 在 New 中校验自定义描述符：注册时被拒绝的与展开后无法解析的描述符
+// 均输出告警，后者从描述符表中移除，避免错误推迟到 Schedule 时才暴露
+func (c *Cron) validateDescriptors() {
+	for _, err := range c.descriptorErrs {
+		logAttrs(context.Background(), c.logger, slog.LevelWarn, "ignored invalid descriptor", errorAttr(err))
+	}
+	c.descriptorErrs = nil
+
+	for _, name := range slices.Sorted(maps.Keys(c.scheduler.descriptors)) {
+		spec := c.scheduler.descriptors[name]
+		if _, err := c.scheduler.parseSingleSchedule(name); err != nil {
+			delete(c.scheduler.descriptors, name)
+			logAttrs(context.Background(), c.logger, slog.LevelWarn, "ignored invalid descriptor",
+				slog.String("descriptor", name), slog.String("spec", spec), errorAttr(err))
+		}
+	}
+}
+
+// ValidateSpec 按调度器当前的解析配置校验表达式，与 Schedule / Update 接受的语法一致
+func (c *Cron) ValidateSpec(spec string) error {
+	normalized, err := normalizeScheduleSpec(spec)
+	if err != nil {
+		return err
+	}
+	_, err = c.scheduler.parseSchedule(normalized)
+	return err
+}
+
+// ParserCacheStats 表达式解析缓存统计：命中、未命中、淘汰次数，当前条目数与容量
+type ParserCacheStats = parser.CacheStats
+
+// SetParserCacheSize 设置表达式解析缓存容量（每种解析选项各自缓存 n 个表达式），n <= 0 时禁用缓存。
+// 缓存为进程级共享，调整容量会清空已缓存的解析结果；默认容量为 1000。
+// 大量任务频繁 Update 时可适当调大，避免缓存反复淘汰。
+func SetParserCacheSize(n int) {
+	parser.SetCacheSize(n)
+}
+
+// GetParserCacheStats 返回表达式解析缓存的统计信息
+func GetParserCacheStats() ParserCacheStats {
+	return parser.GetCacheStats()
+}
+
+// ParseError 表达式解析错误，可通过 errors.As 从 Schedule / Update / ValidateSpec 的返回值中取出，
+// 包含出错字段（Field）、字段序号（Index）、出错片段（Token）及其在表达式中的字节偏移（Offset）。
+// 使用自定义描述符时，位置信息对应展开后的表达式。
+type ParseError = parser.ParseError
+
+// ParseErrorReason 解析错误原因代码
+type ParseErrorReason = parser.ParseErrorReason
+
+const (
+	ReasonEmpty              = parser.ReasonEmpty              // 表达式或子表达式为空
+	ReasonFieldCount         = parser.ReasonFieldCount         // 字段数量不符合解析选项
+	ReasonInvalidNumber      = parser.ReasonInvalidNumber      // 数字或名称无法解析
+	ReasonOutOfRange         = parser.ReasonOutOfRange         // 数值超出字段取值范围
+	ReasonInvalidRange       = parser.ReasonInvalidRange       // 范围写法错误
+	ReasonInvalidStep        = parser.ReasonInvalidStep        // 步长写法错误
+	ReasonInvalidSyntax      = parser.ReasonInvalidSyntax      // L/W/#/BD 等特殊语法错误
+	ReasonUnknownDescriptor  = parser.ReasonUnknownDescriptor  // 未知的描述符
+	ReasonInvalidDescriptor  = parser.ReasonInvalidDescriptor  // 描述符参数错误
+	ReasonDescriptorDisabled = parser.ReasonDescriptorDisabled // 解析选项未启用描述符
+	ReasonInvalidTimezone    = parser.ReasonInvalidTimezone    // 时区前缀错误
+)
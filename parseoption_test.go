@@ -0,0 +1,203 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestWithParserOptions 测试不同解析选项下接受与拒绝的表达式
+func TestWithParserOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ParseOption
+		valid   []string
+		invalid []string
+	}{
+		{
+			name:    "严格5段",
+			options: Minute | Hour | Dom | Month | Dow,
+			valid:   []string{"0 9 * * *", "TZ=UTC 0 9 * * 1-5"},
+			invalid: []string{"0 0 9 * * *", "@daily", "@every 5s"},
+		},
+		{
+			name:    "强制秒字段",
+			options: Second | Minute | Hour | Dom | Month | Dow | Descriptor,
+			valid:   []string{"0 0 9 * * *", "@daily"},
+			invalid: []string{"0 9 * * *"},
+		},
+		{
+			name:    "秒可选",
+			options: SecondOptional | Minute | Hour | Dom | Month | Dow | Descriptor,
+			valid:   []string{"0 9 * * *", "30 0 9 * * *", "@hourly"},
+			invalid: []string{"0 9 * *"},
+		},
+		{
+			name:    "周可选",
+			options: Minute | Hour | Dom | Month | DowOptional,
+			valid:   []string{"0 9 1 *", "0 9 1 * 1"},
+			invalid: []string{"0 9 1"},
+		},
+		{
+			name:    "非法组合",
+			options: SecondOptional | Minute | Hour | Dom | Month | DowOptional,
+			invalid: []string{"0 9 * * *"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(WithLogger(&NoOpLogger{}), WithParserOptions(tt.options))
+			for _, spec := range tt.valid {
+				if err := c.ValidateSpec(spec); err != nil {
+					t.Errorf("ValidateSpec(%q) failed: %v", spec, err)
+				}
+			}
+			for _, spec := range tt.invalid {
+				if err := c.ValidateSpec(spec); err == nil {
+					t.Errorf("ValidateSpec(%q) expected error", spec)
+				}
+				if err := c.Schedule("task", spec, func(ctx context.Context) {}); err == nil {
+					t.Errorf("Schedule(%q) expected error", spec)
+					_ = c.Remove("task")
+				}
+			}
+		})
+	}
+}
+
+// TestWithDescriptor 测试自定义描述符在 Schedule / Update / 组合表达式中展开
+func TestWithDescriptor(t *testing.T) {
+	handler := func(ctx context.Context) {}
+	c := New(
+		WithLogger(&NoOpLogger{}),
+		WithParserOptions(Minute|Hour|Dom|Month|Dow),
+		WithDescriptor("@businesshours", "0 9-17 * * 1-5"),
+		WithDescriptor("@nightly", "0 23 * * *"),
+		WithDescriptor("invalid", "0 1 * * *"),
+	)
+
+	if err := c.Schedule("bh", "TZ=UTC @businesshours", handler); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	next, _ := c.NextRun("bh")
+	next = next.UTC()
+	if next.Minute() != 0 || next.Hour() < 9 || next.Hour() > 17 || next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		t.Fatalf("unexpected next run %v", next)
+	}
+
+	if err := c.Update("bh", "@businesshours | @nightly"); err != nil {
+		t.Fatalf("update with composite descriptors failed: %v", err)
+	}
+	if err := c.ValidateSpec("@daily"); err == nil {
+		t.Fatal("expected built-in descriptor to be rejected when Descriptor option is disabled")
+	}
+	if err := c.ValidateSpec("invalid"); err == nil {
+		t.Fatal("expected descriptor without @ prefix to be ignored")
+	}
+}
+
+// TestWithDescriptorInvalid 测试无效描述符在 New 时告警并被忽略
+func TestWithDescriptorInvalid(t *testing.T) {
+	logger := &logBuffer{}
+	c := New(
+		WithLogger(logger),
+		WithParserOptions(Minute|Hour|Dom|Month|Dow),
+		WithDescriptor("nightly", "0 23 * * *"),
+		WithDescriptor("@empty", " "),
+		WithDescriptor("@broken", "0 25 * * *"),
+		WithDescriptor("@ok", "0 1 * * *"),
+	)
+
+	for _, want := range []string{`descriptor \"nightly\" must start with @`, `descriptor \"@empty\" has an empty spec`, "descriptor=@broken"} {
+		if !logger.contains(want) {
+			t.Fatalf("expected warning containing %q, got %v", want, logger.entries)
+		}
+	}
+	if logger.contains("descriptor=@ok") {
+		t.Fatalf("valid descriptor should not be reported: %v", logger.entries)
+	}
+
+	var parseErr *ParseError
+	// 被移除后按普通描述符处理，而解析选项未启用描述符
+	if err := c.ValidateSpec("@broken"); !errors.As(err, &parseErr) || parseErr.Reason != ReasonDescriptorDisabled {
+		t.Fatalf("expected invalid descriptor to be dropped, got %v", err)
+	}
+	if err := c.ValidateSpec("@ok"); err != nil {
+		t.Fatalf("valid descriptor rejected: %v", err)
+	}
+}
+
+// TestParseErrorDetails 测试 Schedule / Update / ValidateSpec 返回可通过 errors.As 取出的 ParseError
+func TestParseErrorDetails(t *testing.T) {
+	handler := func(ctx context.Context) {}
+	c := New(WithLogger(&NoOpLogger{}))
+
+	err := c.Schedule("bad", "0 9 * * 8", handler)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected ParseError, got %v", err)
+	}
+	if parseErr.Field != "day-of-week" || parseErr.Index != 4 || parseErr.Token != "8" || parseErr.Offset != 8 || parseErr.Reason != ReasonOutOfRange {
+		t.Fatalf("unexpected error detail: %+v", *parseErr)
+	}
+
+	if err := c.Schedule("ok", "0 9 * * *", handler); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	err = c.Update("ok", "0 0 9 * * * | 0 0 25 * * *")
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected ParseError from Update, got %v", err)
+	}
+	if parseErr.Field != "hour" || parseErr.Index != 2 || parseErr.Offset != 18 || parseErr.Spec != "0 0 9 * * * | 0 0 25 * * *" {
+		t.Fatalf("unexpected composite error detail: %+v", *parseErr)
+	}
+
+	err = c.ValidateSpec("@fortnightly")
+	if !errors.As(err, &parseErr) || parseErr.Reason != ReasonUnknownDescriptor || parseErr.Field != "descriptor" {
+		t.Fatalf("expected unknown descriptor ParseError, got %v", err)
+	}
+}
+
+// TestParserCacheSize 测试调整解析缓存容量与统计信息
+func TestParserCacheSize(t *testing.T) {
+	SetParserCacheSize(10)
+	defer SetParserCacheSize(1000)
+
+	c := New(WithLogger(&NoOpLogger{}))
+	before := GetParserCacheStats()
+	for i := range 3 {
+		if err := c.ValidateSpec("0 7 * * *"); err != nil {
+			t.Fatalf("validate %d failed: %v", i, err)
+		}
+	}
+
+	stats := GetParserCacheStats()
+	if stats.Capacity != 10 {
+		t.Fatalf("expected capacity 10, got %d", stats.Capacity)
+	}
+	if stats.Misses-before.Misses != 1 || stats.Hits-before.Hits != 2 {
+		t.Fatalf("expected 1 miss and 2 hits, got %+v (before %+v)", stats, before)
+	}
+
+	// 容量不能被分片数整除时，条目总数仍不超过设置的容量
+	SetParserCacheSize(255)
+	for i := range 600 {
+		if err := c.ValidateSpec(fmt.Sprintf("%d %d * * *", i%60, i/60)); err != nil {
+			t.Fatalf("validate %d failed: %v", i, err)
+		}
+	}
+	if stats := GetParserCacheStats(); stats.Entries == 0 || stats.Entries > 255 {
+		t.Fatalf("expected at most 255 entries, got %+v", stats)
+	}
+
+	SetParserCacheSize(0)
+	if stats := GetParserCacheStats(); stats.Capacity != 0 || stats.Entries != 0 {
+		t.Fatalf("expected disabled cache, got %+v", stats)
+	}
+	if err := c.ValidateSpec("0 7 * * *"); err != nil {
+		t.Fatalf("validate with disabled cache failed: %v", err)
+	}
+}
@@ -110,7 +110,7 @@ func TestPreciseConcurrencyControl(t *testing.T) {
 					<-startCh // 等待同时开始信号
 
 					// 直接调用executeTask，如果被跳过会在日志中显示
-					scheduler.executeTask(runner)
+					scheduler.executeTask(runner, TriggerManual, time.Time{})
 				}(i)
 			}
 
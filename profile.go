@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"context"
+	"runtime/metrics"
+	"runtime/pprof"
+	"sync/atomic"
+	"time"
+
+	"github.com/darkit/cron/history"
+)
+
+// ProfileLabelTrigger 执行协程的 pprof 标签键：触发来源。任务 ID 的标签键为 LogKeyTaskID，
+// 启用泄漏检测时还会带有 LogKeyExecutionID
+const ProfileLabelTrigger = "trigger"
+
+// heapAllocsMetric 累计堆分配字节数。小对象按 span 计入，因此差值是近似值
+const heapAllocsMetric = "/gc/heap/allocs:bytes"
+
+// executionLabels 返回一次执行的 pprof 标签
+func executionLabels(taskID string, trigger TriggerSource) pprof.LabelSet {
+	return pprof.Labels(LogKeyTaskID, taskID, ProfileLabelTrigger, string(trigger))
+}
+
+// runLabeled 在带有任务标签的协程上下文中执行 fn，任务协程及其派生协程继承标签，
+// CPU / goroutine profile 可按 task_id、trigger 归属
+func runLabeled(ctx context.Context, taskID string, trigger TriggerSource, fn func(context.Context)) {
+	pprof.Do(ctx, executionLabels(taskID, trigger), fn)
+}
+
+// resourceSampler 按任务每 every 次执行采样一次资源使用。堆分配量是进程级指标，
+// 只有采样期间没有其他任务执行时才归属于该任务
+type resourceSampler struct {
+	every    uint64
+	inflight atomic.Int64  // 进行中的执行数
+	starts   atomic.Uint64 // 执行开始计数，用于判断采样期间是否有其他执行开始
+}
+
+// resourceSample 进行中的一次采样
+type resourceSample struct {
+	starts    uint64 // 开始时的执行计数
+	exclusive bool   // 开始时没有其他执行
+	allocs    uint64 // 开始时的累计堆分配
+}
+
+func newResourceSampler(every int) *resourceSampler {
+	return &resourceSampler{every: uint64(every)}
+}
+
+// begin 登记一次执行开始，seq 为该任务的执行序号（从 1 开始），未被采样时返回 nil。
+// 每次 begin 必须对应一次 end
+func (r *resourceSampler) begin(seq uint64) *resourceSample {
+	inflight := r.inflight.Add(1)
+	starts := r.starts.Add(1)
+	if (seq-1)%r.every != 0 {
+		return nil
+	}
+	return &resourceSample{starts: starts, exclusive: inflight == 1, allocs: heapAllocs()}
+}
+
+// end 登记一次执行结束，返回采样结果；busy 为各次尝试的执行耗时合计
+func (r *resourceSampler) end(sample *resourceSample, busy time.Duration) *history.ResourceUsage {
+	defer r.inflight.Add(-1)
+	if sample == nil {
+		return nil
+	}
+	usage := &history.ResourceUsage{WallTime: busy}
+	if sample.exclusive && r.starts.Load() == sample.starts {
+		usage.AllocBytes = heapAllocs() - sample.allocs
+		usage.AllocMeasured = true
+	}
+	return usage
+}
+
+// heapAllocs 读取累计堆分配字节数
+func heapAllocs() uint64 {
+	sample := []metrics.Sample{{Name: heapAllocsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darkit/cron/history"
+)
+
+// capturingRecorder 记录完整执行记录的历史记录器
+type capturingRecorder struct {
+	stubRecorder
+	mu      sync.Mutex
+	records []*history.ExecutionRecord
+}
+
+func (r *capturingRecorder) RecordExecution(record *history.ExecutionRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+// allocSink 防止测试中的分配被编译器优化
+var allocSink []byte
+
+// TestExecutionProfileLabels 测试执行上下文与派生协程带有 task_id / trigger 标签
+func TestExecutionProfileLabels(t *testing.T) {
+	c := New(WithLogger(&NoOpLogger{}))
+	defer c.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	labels := make(chan [2]string, 1)
+	if err := c.Schedule("profiled", "0 0 1 1 *", func(ctx context.Context) {
+		taskID, _ := pprof.Label(ctx, LogKeyTaskID)
+		trigger, _ := pprof.Label(ctx, ProfileLabelTrigger)
+		go func() {
+			<-release
+		}()
+		labels <- [2]string{taskID, trigger}
+	}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.RunNow("profiled"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+
+	if got := <-labels; got != [2]string{"profiled", string(TriggerManual)} {
+		t.Fatalf("context labels = %v", got)
+	}
+	if n := labeledGoroutines(LogKeyTaskID)["profiled"]; n < 1 {
+		t.Fatalf("spawned goroutine should inherit task label, got %d", n)
+	}
+}
+
+// TestResourceSampling 测试按执行次数采样，并将耗时与分配量写入 Stats 与历史记录
+func TestResourceSampling(t *testing.T) {
+	recorder := &capturingRecorder{}
+	finished := make(chan Event, 1)
+	c := New(
+		WithLogger(&NoOpLogger{}),
+		WithHistoryRecorder(recorder),
+		WithResourceSampling(2),
+		WithEventHook(func(ev Event) {
+			if ev.Type == EventTaskFinished {
+				finished <- ev
+			}
+		}),
+	)
+	defer c.Close()
+
+	if err := c.Schedule("alloc", "0 0 1 1 *", func(ctx context.Context) {
+		allocSink = make([]byte, 1<<20)
+		time.Sleep(5 * time.Millisecond)
+	}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	for range 3 {
+		if err := c.RunNow("alloc"); err != nil {
+			t.Fatalf("run now failed: %v", err)
+		}
+		<-finished
+	}
+
+	stats, _ := c.GetStats("alloc")
+	if stats.ResourceSamples != 2 || stats.AllocSamples != 2 {
+		t.Fatalf("expected executions 1 and 3 to be sampled: %+v", stats)
+	}
+	if stats.LastWallTime < 5*time.Millisecond || stats.LastAllocBytes < 1<<20 || stats.AvgAllocBytes < 1<<20 || stats.MaxAllocBytes < stats.LastAllocBytes {
+		t.Fatalf("unexpected resource stats: %+v", stats)
+	}
+
+	// 历史记录在结束事件之后写入
+	deadline := time.Now().Add(time.Second)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	for len(recorder.records) < 3 && time.Now().Before(deadline) {
+		recorder.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		recorder.mu.Lock()
+	}
+	if len(recorder.records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(recorder.records))
+	}
+	for i, record := range recorder.records {
+		if sampled := i != 1; sampled != (record.Resources != nil) {
+			t.Fatalf("record %d resources = %+v", i, record.Resources)
+		}
+	}
+	if usage := recorder.records[2].Resources; !usage.AllocMeasured || usage.AllocBytes < 1<<20 {
+		t.Fatalf("unexpected resource usage: %+v", usage)
+	}
+}
+
+// TestResourceSamplerOverlap 测试采样期间有其他执行时不归属分配量
+func TestResourceSamplerOverlap(t *testing.T) {
+	r := newResourceSampler(1)
+
+	a := r.begin(1)
+	b := r.begin(1)
+	if usage := r.end(b, time.Millisecond); usage.AllocMeasured || usage.WallTime != time.Millisecond {
+		t.Fatalf("execution started during another should not be attributed: %+v", usage)
+	}
+	if usage := r.end(a, 0); usage.AllocMeasured {
+		t.Fatalf("execution overlapped by another should not be attributed: %+v", usage)
+	}
+
+	if usage := r.end(r.begin(1), 0); !usage.AllocMeasured {
+		t.Fatalf("exclusive execution should be attributed: %+v", usage)
+	}
+	if usage := newResourceSampler(3).end(nil, 0); usage != nil {
+		t.Fatalf("unsampled execution should return nil: %+v", usage)
+	}
+}
@@ -3,6 +3,7 @@ package cron
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"runtime"
 )
 
@@ -26,9 +27,10 @@ func NewDefaultPanicHandler(logger Logger) *DefaultPanicHandler {
 
 // HandlePanic 默认的panic处理实现
 func (h *DefaultPanicHandler) HandlePanic(taskID string, panicValue any, stack []byte) {
-	if h.logger != nil {
-		h.logger.Errorf("PANIC in task %s: %v\nStack trace:\n%s", taskID, panicValue, stack)
-	}
+	logAttrs(context.Background(), h.logger, slog.LevelError, "task panicked",
+		slog.String(LogKeyTaskID, taskID),
+		slog.Any("panic", panicValue),
+		slog.String("stack", string(stack)))
 }
 
 // SafeCall 安全调用函数，捕获并处理panic
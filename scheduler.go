@@ -3,11 +3,14 @@ package cron
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"maps"
-	"runtime"
 	"runtime/debug"
+	"runtime/pprof"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/darkit/cron/history"
@@ -29,6 +32,22 @@ type scheduler struct {
 	rootCtx      context.Context
 	recorder     history.Recorder // 历史记录器（可选）
 	eventHook    EventHook
+	calendar     HolidayCalendar // 营业日语法使用的节假日日历（可选）
+	location     *time.Location  // 默认时区（可选）
+
+	parserOptions ParseOption       // 表达式解析选项，0 表示按字段数自动识别
+	descriptors   map[string]string // 自定义描述符
+
+	lagWarnThreshold time.Duration // 调度延迟告警阈值，0 表示不告警
+	tracer           Tracer        // 执行追踪（可选）
+	events           *eventBus     // 事件订阅总线
+
+	overdueCheckInterval time.Duration            // 成功 SLA 检查间隔，同时是心跳间隔
+	heartbeat            atomic.Int64             // 后台检查循环最近一次完成检查的时间（UnixNano）
+	stalled              atomic.Pointer[[]string] // 最近一次检查发现的停滞调度循环
+
+	leaks     *leakDetector    // 协程泄漏检测（可选）
+	resources *resourceSampler // 执行资源采样（可选）
 }
 
 // newScheduler 创建一个新的调度器
@@ -47,12 +66,58 @@ func newSchedulerWithContext(rootCtx context.Context) *scheduler {
 		ctx:     ctx,
 		cancel:  cancel,
 		rootCtx: rootCtx,
+		events:  newEventBus(),
+
+		overdueCheckInterval: DefaultOverdueCheckInterval,
+	}
+}
+
+// parseSchedule 解析 cron 表达式，支持 | & ! 组合语法
+func (s *scheduler) parseSchedule(spec string) (parser.Schedule, error) {
+	if parser.IsComposite(spec) {
+		return parser.ParseComposite(spec, s.parseSingleSchedule)
+	}
+	return s.parseSingleSchedule(spec)
+}
+
+// parseSingleSchedule 解析单个 cron 表达式：先展开自定义描述符，
+// 配置了解析选项时使用对应解析器，否则按字段数自动选择5段或6段格式
+func (s *scheduler) parseSingleSchedule(spec string) (parser.Schedule, error) {
+	spec = s.expandDescriptor(spec)
+	if s.parserOptions != 0 {
+		p, err := parser.NewParser(parser.ParseOption(s.parserOptions))
+		if err != nil {
+			return nil, fmt.Errorf("invalid parser options: %w", err)
+		}
+		return p.Parse(spec)
 	}
+	return parseAutoDetect(spec)
 }
 
-// parseSchedule 解析 cron 表达式，兼容5段与6段格式
-func parseSchedule(spec string) (parser.Schedule, error) {
+// expandDescriptor 将自定义描述符替换为对应的表达式，保留 TZ= 前缀
+func (s *scheduler) expandDescriptor(spec string) string {
+	if len(s.descriptors) == 0 {
+		return spec
+	}
+
+	prefix, rest := "", strings.TrimSpace(spec)
+	if strings.HasPrefix(rest, "TZ=") || strings.HasPrefix(rest, "CRON_TZ=") {
+		tz, remaining, _ := strings.Cut(rest, " ")
+		prefix, rest = tz+" ", strings.TrimSpace(remaining)
+	}
+	if expanded, ok := s.descriptors[rest]; ok {
+		return prefix + expanded
+	}
+	return spec
+}
+
+// parseAutoDetect 按字段数选择5段或6段解析器
+func parseAutoDetect(spec string) (parser.Schedule, error) {
 	fields := strings.Fields(strings.TrimSpace(spec))
+	// TZ=/CRON_TZ= 前缀不计入字段数
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ=")) {
+		fields = fields[1:]
+	}
 	specFields := len(fields)
 
 	if specFields == 5 {
@@ -63,6 +128,152 @@ func parseSchedule(spec string) (parser.Schedule, error) {
 	return p.Parse(spec)
 }
 
+// parseTaskSchedule 解析任务的调度表达式，并绑定时区、节假日日历、夏令时策略、有效时段与截止时间
+// 时区优先级：TZ= 前缀 / JobOptions.Location > fallback > 调度器默认时区；前缀与选项不一致时报错
+func (s *scheduler) parseTaskSchedule(spec string, opts JobOptions, fallback *time.Location) (parser.Schedule, error) {
+	schedule, err := s.parseSchedule(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefixLoc, explicit := parser.ExplicitLocation(schedule); explicit {
+		if opts.Location != nil && opts.Location.String() != prefixLoc.String() {
+			return nil, fmt.Errorf("time zone prefix %s conflicts with job option Location %s", prefixLoc, opts.Location)
+		}
+	} else if opts.Location != nil {
+		schedule = parser.WithLocation(schedule, opts.Location)
+	} else if fallback != nil {
+		schedule = parser.WithLocation(schedule, fallback)
+	} else if s.location != nil {
+		schedule = parser.WithLocation(schedule, s.location)
+	}
+
+	schedule = bindCalendar(schedule, s.calendar)
+	schedule = parser.WithDSTPolicy(schedule, parser.DSTPolicy(opts.DSTPolicy))
+	schedule = bindActiveWindows(schedule, opts.ActiveWindows)
+	return parser.Until(schedule, opts.EndAt), nil
+}
+
+// bindActiveWindows 将有效时段绑定到调度，未指定时区的时段沿用调度时区
+func bindActiveWindows(schedule parser.Schedule, windows []ActiveWindow) parser.Schedule {
+	if len(windows) == 0 {
+		return schedule
+	}
+
+	fallback, _ := parser.ExplicitLocation(schedule)
+	converted := make([]parser.Window, len(windows))
+	for i, window := range windows {
+		var weekdays uint8
+		for _, day := range window.Weekdays {
+			weekdays |= 1 << uint(day)
+		}
+		loc := window.Location
+		if loc == nil {
+			loc = fallback
+		}
+		converted[i] = parser.Window{Start: window.Start, End: window.End, Weekdays: weekdays, Location: loc}
+	}
+	return parser.WithWindows(schedule, converted)
+}
+
+// constantDelay 返回固定间隔调度；截止时间与有效时段只截断触发点，不影响按间隔推算，
+// 推算结果需经 clampToWindows 落入有效时段
+func constantDelay(schedule parser.Schedule) (*parser.ConstantDelaySchedule, bool) {
+	for {
+		switch typed := schedule.(type) {
+		case *parser.ConstantDelaySchedule:
+			return typed, true
+		case *parser.UntilSchedule:
+			schedule = typed.Schedule
+		case *parser.WindowSchedule:
+			schedule = typed.Schedule
+		default:
+			return nil, false
+		}
+	}
+}
+
+// clampToWindows 将按间隔推算的触发点移入有效时段：时段外时推迟到下一个时段开始，未配置时段时原样返回
+func clampToWindows(schedule parser.Schedule, t time.Time) time.Time {
+	if until, ok := schedule.(*parser.UntilSchedule); ok {
+		schedule = until.Schedule
+	}
+	if window, ok := schedule.(*parser.WindowSchedule); ok {
+		return window.Clamp(t)
+	}
+	return t
+}
+
+// pastEnd 判断触发点是否已超过调度的截止时间
+func pastEnd(schedule parser.Schedule, t time.Time) bool {
+	until, ok := schedule.(*parser.UntilSchedule)
+	return ok && t.After(until.End)
+}
+
+// scheduleTimeZone 返回调度实际生效的时区名称，未显式指定时为本地时区
+func scheduleTimeZone(schedule parser.Schedule) string {
+	if loc, explicit := parser.ExplicitLocation(schedule); explicit {
+		return loc.String()
+	}
+	return time.Local.String()
+}
+
+// timeZone 返回指定任务生效的时区名称
+func (s *scheduler) timeZone(id string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runner, exists := s.tasks[id]
+	if !exists {
+		return ""
+	}
+	runner.mu.RLock()
+	defer runner.mu.RUnlock()
+	return scheduleTimeZone(runner.schedule)
+}
+
+// hasListeners 是否存在事件钩子或订阅者
+func (s *scheduler) hasListeners() bool {
+	return s.eventHook != nil || s.events.active()
+}
+
+// emit 分发事件并补全事件时间：先非阻塞投递给订阅者，再同步调用事件钩子。
+// 调用方不得持有 s.mu 或 runner.mu，避免钩子回调调度器时死锁
+func (s *scheduler) emit(ev Event) {
+	if !s.hasListeners() {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if s.events.active() {
+		s.events.publish(ev)
+	}
+	if s.eventHook != nil {
+		s.eventHook(ev)
+	}
+}
+
+// emitTask 分发任务事件，附带任务 ID 与标签快照
+func (s *scheduler) emitTask(runner *taskRunner, ev Event) {
+	if !s.hasListeners() {
+		return
+	}
+	runner.mu.RLock()
+	ev.TaskID = runner.task.ID
+	ev.Labels = cloneLabels(runner.task.Labels)
+	runner.mu.RUnlock()
+	s.emit(ev)
+}
+
+// notify 唤醒等待中的调度循环，使其按最新计划重新计时
+func (r *taskRunner) notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
 // resetFailure 重置失败计数
 func (r *taskRunner) resetFailure() {
 	r.failure.mu.Lock()
@@ -71,10 +282,10 @@ func (r *taskRunner) resetFailure() {
 	r.failure.mu.Unlock()
 }
 
-// recordFailure 记录一次失败并在达到阈值时自动暂停
-func (r *taskRunner) recordFailure(window time.Duration, threshold int, pauseDuration time.Duration, logger Logger, taskID string) {
+// recordFailure 记录一次失败并在达到阈值时自动暂停，返回是否触发暂停及自动恢复时间
+func (r *taskRunner) recordFailure(window time.Duration, threshold int, pauseDuration time.Duration) (time.Time, bool) {
 	if threshold <= 0 {
-		return
+		return time.Time{}, false
 	}
 
 	now := time.Now()
@@ -99,10 +310,9 @@ func (r *taskRunner) recordFailure(window time.Duration, threshold int, pauseDur
 		r.nextRun = r.pauseUntil
 		r.mu.Unlock()
 
-		if logger != nil {
-			logger.Warnf("Task %s paused for %v after %d failures", taskID, pauseDuration, threshold)
-		}
+		return now.Add(pauseDuration), true
 	}
+	return time.Time{}, false
 }
 
 // taskRunner 运行任务的实体
@@ -119,6 +329,9 @@ type taskRunner struct {
 	cancel        context.CancelFunc
 	mu            sync.RWMutex
 	semaphore     chan struct{} // 并发控制
+	wake          chan struct{} // 调度计划变化时唤醒调度循环
+	executions    atomic.Uint64 // 执行序号，用于资源采样
+	dueAt         atomic.Int64  // 调度循环等待或正在处理的触发时刻（monoNanos），0 表示没有待处理的触发
 
 	// 重试状态（线程安全）
 	retry struct {
@@ -140,6 +353,13 @@ func cloneJobOptions(opts JobOptions) JobOptions {
 		cloned.Labels = make(map[string]string, len(opts.Labels))
 		maps.Copy(cloned.Labels, opts.Labels)
 	}
+	if opts.ActiveWindows != nil {
+		cloned.ActiveWindows = make([]ActiveWindow, len(opts.ActiveWindows))
+		for i, window := range opts.ActiveWindows {
+			window.Weekdays = slices.Clone(window.Weekdays)
+			cloned.ActiveWindows[i] = window
+		}
+	}
 	return cloned
 }
 
@@ -163,8 +383,8 @@ func remainingRunsFromOptions(opts JobOptions) int {
 
 // nextOccurrenceOnOrAfter 返回不早于指定时间的下一个触发点。
 func nextOccurrenceOnOrAfter(schedule parser.Schedule, from time.Time) time.Time {
-	if _, ok := schedule.(*parser.ConstantDelaySchedule); ok {
-		return from
+	if _, ok := constantDelay(schedule); ok {
+		return clampToWindows(schedule, from)
 	}
 
 	candidate := schedule.Next(from.Add(-time.Second))
@@ -176,8 +396,8 @@ func nextOccurrenceOnOrAfter(schedule parser.Schedule, from time.Time) time.Time
 
 // defaultNextRun 计算未指定 StartAt 时的首次触发点。
 func defaultNextRun(schedule parser.Schedule, now time.Time) time.Time {
-	if _, ok := schedule.(*parser.ConstantDelaySchedule); ok {
-		return now
+	if _, ok := constantDelay(schedule); ok {
+		return clampToWindows(schedule, now)
 	}
 	return schedule.Next(now)
 }
@@ -185,11 +405,18 @@ func defaultNextRun(schedule parser.Schedule, now time.Time) time.Time {
 // planInitialState 计算任务初次加入调度器时的运行状态。
 func planInitialState(schedule parser.Schedule, opts JobOptions, now time.Time) (time.Time, int, bool) {
 	remainingRuns := remainingRunsFromOptions(opts)
+	if pastEnd(schedule, now) {
+		return time.Time{}, 0, true
+	}
+	// 启动任务的触发时间由 start 决定
+	if _, ok := parser.StartupDelay(schedule); ok {
+		return time.Time{}, remainingRuns, false
+	}
 	if opts.StartAt.IsZero() {
 		return defaultNextRun(schedule, now), remainingRuns, false
 	}
 
-	if delaySchedule, ok := schedule.(*parser.ConstantDelaySchedule); ok {
+	if delaySchedule, ok := constantDelay(schedule); ok {
 		nextRun := opts.StartAt
 		if !opts.StartAt.After(now) {
 			delay := delaySchedule.Delay
@@ -212,6 +439,10 @@ func planInitialState(schedule parser.Schedule, opts JobOptions, now time.Time)
 				remainingRuns -= steps
 			}
 		}
+		nextRun = clampToWindows(schedule, nextRun)
+		if nextRun.IsZero() || pastEnd(schedule, nextRun) {
+			return time.Time{}, 0, true
+		}
 		return nextRun, remainingRuns, false
 	}
 
@@ -243,8 +474,11 @@ func recomputeNextRun(schedule parser.Schedule, startAt time.Time, remainingRuns
 	if remainingRuns == 0 {
 		return time.Time{}, true
 	}
+	if _, ok := parser.StartupDelay(schedule); ok {
+		return time.Time{}, false
+	}
 	if !startAt.IsZero() {
-		if delaySchedule, ok := schedule.(*parser.ConstantDelaySchedule); ok {
+		if delaySchedule, ok := constantDelay(schedule); ok {
 			nextRun := startAt
 			if !startAt.After(now) {
 				delay := delaySchedule.Delay
@@ -258,7 +492,8 @@ func recomputeNextRun(schedule parser.Schedule, startAt time.Time, remainingRuns
 					nextRun = nextRun.Add(delay)
 				}
 			}
-			return nextRun, nextRun.IsZero()
+			nextRun = clampToWindows(schedule, nextRun)
+			return nextRun, nextRun.IsZero() || pastEnd(schedule, nextRun)
 		}
 
 		if startAt.After(now) {
@@ -268,7 +503,7 @@ func recomputeNextRun(schedule parser.Schedule, startAt time.Time, remainingRuns
 	}
 
 	nextRun := nextOccurrenceOnOrAfter(schedule, now)
-	return nextRun, nextRun.IsZero()
+	return nextRun, nextRun.IsZero() || pastEnd(schedule, nextRun)
 }
 
 // advancePlanAfterTrigger 根据当前已触发的计划点推进任务状态。
@@ -293,6 +528,10 @@ func (s *scheduler) advancePlanAfterTrigger(runner *taskRunner, triggerTime time
 	if consume() {
 		return time.Time{}, 0, true
 	}
+	// 启动任务本次启动只触发一次，等待下次 start
+	if _, ok := parser.StartupDelay(schedule); ok {
+		return time.Time{}, remainingRuns, false
+	}
 
 	nextRun := schedule.Next(triggerTime)
 	if nextRun.IsZero() {
@@ -310,7 +549,7 @@ func (s *scheduler) advancePlanAfterTrigger(runner *taskRunner, triggerTime time
 		catchUps := 0
 		for nextRun.Before(now) {
 			if catchUps < maxCatchUp {
-				s.executeTask(runner)
+				s.executeTask(runner, TriggerCatchUp, nextRun)
 				if consume() {
 					return time.Time{}, 0, true
 				}
@@ -355,7 +594,7 @@ func (s *scheduler) addTask(task *Task) error {
 	}
 
 	// 解析cron表达式
-	schedule, err := parseSchedule(task.Schedule)
+	schedule, err := s.parseTaskSchedule(task.Schedule, task.Options, nil)
 	if err != nil {
 		return fmt.Errorf("invalid cron spec %s: %w", task.Schedule, err)
 	}
@@ -376,6 +615,11 @@ func (s *scheduler) addTask(task *Task) error {
 		remainingRuns: remainingRuns,
 		ctx:           ctx,
 		cancel:        cancel,
+		wake:          make(chan struct{}, 1),
+	}
+	// 调度器运行中添加的启动任务视为本次启动的一部分，立即按延迟触发
+	if delay, ok := parser.StartupDelay(schedule); ok && s.running {
+		runner.nextRun = now.Add(delay)
 	}
 
 	// 为MaxConcurrent > 0的情况预先创建semaphore
@@ -394,21 +638,23 @@ func (s *scheduler) addTask(task *Task) error {
 	return nil
 }
 
-// removeTask 移除一个任务
-func (s *scheduler) removeTask(id string) error {
+// removeTask 移除一个任务，返回被移除任务的标签快照，供事件分发使用
+func (s *scheduler) removeTask(id string) (map[string]string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	runner, exists := s.tasks[id]
 	if !exists {
-		return fmt.Errorf("task %s not found", id)
+		return nil, fmt.Errorf("task %s not found", id)
 	}
 
 	// 停止任务
 	runner.cancel()
 	delete(s.tasks, id)
 
-	return nil
+	runner.mu.RLock()
+	defer runner.mu.RUnlock()
+	return cloneLabels(runner.task.Labels), nil
 }
 
 // expireTask 在计划执行完毕或计划窗口过期后自动清理任务。
@@ -433,23 +679,32 @@ func (s *scheduler) expireTask(id string) {
 	if s.monitor != nil {
 		s.monitor.removeTask(id)
 	}
-	if s.logger != nil {
-		s.logger.Infof("Task %s expired and removed automatically", id)
-	}
+	logAttrs(context.Background(), s.logger, slog.LevelInfo, "task expired and removed automatically", slog.String(LogKeyTaskID, id))
+	s.emitTask(runner, Event{Type: EventTaskExpired})
 }
 
 // updateTask 更新任务的调度表达式和可选配置
 func (s *scheduler) updateTask(id, schedule string, opts *JobOptions) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	runner, exists := s.tasks[id]
 	if !exists {
+		s.mu.Unlock()
 		return fmt.Errorf("task %s not found", id)
 	}
 
-	parsed, err := parseSchedule(schedule)
+	// 未传入新配置时沿用当前配置与生效时区，避免重新输入表达式时丢失 TZ= 前缀指定的时区
+	parseOpts := opts
+	var inheritedLoc *time.Location
+	if parseOpts == nil {
+		runner.mu.RLock()
+		current := runner.task.Options
+		inheritedLoc, _ = parser.ExplicitLocation(runner.schedule)
+		runner.mu.RUnlock()
+		parseOpts = &current
+	}
+	parsed, err := s.parseTaskSchedule(schedule, *parseOpts, inheritedLoc)
 	if err != nil {
+		s.mu.Unlock()
 		return fmt.Errorf("invalid cron spec %s: %w", schedule, err)
 	}
 
@@ -466,6 +721,7 @@ func (s *scheduler) updateTask(id, schedule string, opts *JobOptions) error {
 		nextRun, remainingRuns, expired = planInitialState(parsed, *opts, now)
 		if expired {
 			runner.mu.Unlock()
+			s.mu.Unlock()
 			return fmt.Errorf("task %s schedule already expired", id)
 		}
 	} else {
@@ -473,6 +729,7 @@ func (s *scheduler) updateTask(id, schedule string, opts *JobOptions) error {
 		nextRun, expired = recomputeNextRun(parsed, currentOptions.StartAt, currentRemainingRuns, now)
 		if expired {
 			runner.mu.Unlock()
+			s.mu.Unlock()
 			return fmt.Errorf("task %s schedule already expired", id)
 		}
 	}
@@ -495,15 +752,21 @@ func (s *scheduler) updateTask(id, schedule string, opts *JobOptions) error {
 		labels = cloneLabels(runner.task.Labels)
 	}
 	misfirePolicy := string(runner.task.Options.MisfirePolicy)
+	expectSuccessWithin := runner.task.Options.ExpectSuccessWithin
 	runner.mu.Unlock()
+	s.mu.Unlock()
+	runner.notify()
 
 	if s.monitor != nil {
 		s.monitor.updateSchedule(id, schedule)
+		s.monitor.setTimeZone(id, scheduleTimeZone(parsed))
 		if opts != nil {
 			s.monitor.updateTaskMeta(id, labels, misfirePolicy)
+			s.monitor.setExpectSuccessWithin(id, expectSuccessWithin)
 		}
 	}
 
+	s.emitTask(runner, Event{Type: EventTaskUpdated})
 	return nil
 }
 
@@ -525,6 +788,7 @@ func (s *scheduler) pauseTask(id string) error {
 	if s.monitor != nil {
 		s.monitor.setPauseUntil(id, time.Now())
 	}
+	s.emitTask(runner, Event{Type: EventTaskPaused})
 	return nil
 }
 
@@ -556,10 +820,12 @@ func (s *scheduler) resumeTask(id string) error {
 		s.expireTask(id)
 		return nil
 	}
+	runner.notify()
 
 	if s.monitor != nil {
 		s.monitor.setPauseUntil(id, time.Time{})
 	}
+	s.emitTask(runner, Event{Type: EventTaskResumed})
 	return nil
 }
 
@@ -584,11 +850,9 @@ func (s *scheduler) runNow(id string) error {
 		return fmt.Errorf("task %s is paused", id)
 	}
 
-	if s.logger != nil {
-		s.logger.Infof("Trigger task %s to run immediately", id)
-	}
+	logAttrs(context.Background(), s.logger, slog.LevelInfo, "task triggered manually", slog.String(LogKeyTaskID, id))
 
-	s.executeTask(runner)
+	s.executeTask(runner, TriggerManual, time.Time{})
 	return nil
 }
 
@@ -603,12 +867,23 @@ func (s *scheduler) start() error {
 
 	s.running = true
 
-	// 启动所有任务
+	// 启动所有任务，启动任务（@reboot / @start）按延迟安排本次触发
+	now := time.Now()
 	for _, runner := range s.tasks {
+		runner.mu.Lock()
+		if delay, ok := parser.StartupDelay(runner.schedule); ok && !runner.paused && runner.remainingRuns != 0 {
+			runner.nextRun = now.Add(delay)
+		}
+		runner.mu.Unlock()
 		s.wg.Add(1)
 		go s.runTask(runner)
 	}
 
+	s.heartbeat.Store(now.UnixNano())
+	s.stalled.Store(nil)
+	s.wg.Add(1)
+	go s.watchOverdue(s.ctx)
+
 	return nil
 }
 
@@ -653,6 +928,8 @@ func (s *scheduler) stopWithTimeout(timeout time.Duration) {
 		runner.mu.Unlock()
 	}
 	s.mu.Unlock()
+
+	s.emit(Event{Type: EventSchedulerStopped})
 }
 
 // waitExecutions 等待正在执行的任务完成，超时则提前返回
@@ -674,9 +951,7 @@ func (s *scheduler) waitExecutions(timeout time.Duration) {
 	select {
 	case <-done:
 	case <-timer.C:
-		if s.logger != nil {
-			s.logger.Warnf("Stop waiting for running tasks timed out after %v", timeout)
-		}
+		logAttrs(context.Background(), s.logger, slog.LevelWarn, "stop waiting for running tasks timed out", slog.Duration("timeout", timeout))
 	}
 }
 
@@ -712,6 +987,7 @@ func (s *scheduler) getTaskInfo(id string) (*TaskInfo, bool) {
 		Labels:        cloneLabels(runner.task.Labels),
 		NextRun:       runner.nextRun,
 		RemainingRuns: runner.remainingRuns,
+		TimeZone:      scheduleTimeZone(runner.schedule),
 		IsPaused:      runner.paused,
 		IsRunning:     runner.running,
 		CreatedAt:     runner.task.created,
@@ -733,6 +1009,7 @@ func (s *scheduler) getAllTaskInfo() []*TaskInfo {
 			Labels:        cloneLabels(runner.task.Labels),
 			NextRun:       runner.nextRun,
 			RemainingRuns: runner.remainingRuns,
+			TimeZone:      scheduleTimeZone(runner.schedule),
 			IsPaused:      runner.paused,
 			IsRunning:     runner.running,
 			CreatedAt:     runner.task.created,
@@ -746,10 +1023,10 @@ func (s *scheduler) getAllTaskInfo() []*TaskInfo {
 // pauseAll 暂停所有任务
 func (s *scheduler) pauseAll() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
+	runners := make([]*taskRunner, 0, len(s.tasks))
 	for id, runner := range s.tasks {
 		runner.mu.Lock()
+		wasPaused := runner.paused
 		runner.paused = true
 		runner.pauseUntil = time.Time{}
 		runner.mu.Unlock()
@@ -757,16 +1034,28 @@ func (s *scheduler) pauseAll() {
 		if s.monitor != nil {
 			s.monitor.setPauseUntil(id, time.Now())
 		}
+		if !wasPaused {
+			runners = append(runners, runner)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, runner := range runners {
+		s.emitTask(runner, Event{Type: EventTaskPaused})
 	}
 }
 
 // resumeAll 恢复所有任务
 func (s *scheduler) resumeAll() {
-	var expiredIDs []string
+	var (
+		expiredIDs []string
+		resumed    []*taskRunner
+	)
 
 	s.mu.Lock()
 	for id, runner := range s.tasks {
 		runner.mu.Lock()
+		wasPaused := runner.paused
 		runner.paused = false
 		runner.pauseUntil = time.Time{}
 		nextRun, expired := recomputeNextRun(runner.schedule, runner.task.Options.StartAt, runner.remainingRuns, time.Now())
@@ -775,6 +1064,9 @@ func (s *scheduler) resumeAll() {
 			expiredIDs = append(expiredIDs, id)
 		} else {
 			runner.nextRun = nextRun
+			if wasPaused {
+				resumed = append(resumed, runner)
+			}
 		}
 		runner.mu.Unlock()
 
@@ -784,6 +1076,9 @@ func (s *scheduler) resumeAll() {
 	}
 	s.mu.Unlock()
 
+	for _, runner := range resumed {
+		s.emitTask(runner, Event{Type: EventTaskResumed})
+	}
 	for _, id := range expiredIDs {
 		s.expireTask(id)
 	}
@@ -808,6 +1103,9 @@ func (s *scheduler) nextRun(id string) (time.Time, error) {
 // runTask 运行单个任务
 func (s *scheduler) runTask(runner *taskRunner) {
 	defer s.wg.Done()
+	// 启动与被唤醒时需要立即计算计划，视为已到期
+	runner.dueAt.Store(monoNanos(time.Now()))
+	defer runner.dueAt.Store(0)
 
 	timer := time.NewTimer(0)
 	if !timer.Stop() {
@@ -821,7 +1119,19 @@ func (s *scheduler) runTask(runner *taskRunner) {
 	for {
 		runner.mu.RLock()
 		next := runner.nextRun
+		_, startup := parser.StartupDelay(runner.schedule)
 		runner.mu.RUnlock()
+		if next.IsZero() && startup {
+			// 启动任务已触发，等待调度计划变化或调度器停止
+			runner.dueAt.Store(0)
+			select {
+			case <-runner.ctx.Done():
+				return
+			case <-runner.wake:
+				runner.dueAt.Store(monoNanos(time.Now()))
+				continue
+			}
+		}
 		if next.IsZero() {
 			s.expireTask(runner.task.ID)
 			return
@@ -829,6 +1139,8 @@ func (s *scheduler) runTask(runner *taskRunner) {
 
 		wait := max(time.Until(next), 0)
 
+		// 到期后直到回到这里之前（同步执行任务函数期间除外），dueAt 保持为已到期的时刻，供 stalledTasks 判断循环是否停滞
+		runner.dueAt.Store(monoNanos(time.Now().Add(wait)))
 		timer.Reset(wait)
 		select {
 		case <-runner.ctx.Done():
@@ -839,6 +1151,15 @@ func (s *scheduler) runTask(runner *taskRunner) {
 				}
 			}
 			return
+		case <-runner.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			runner.dueAt.Store(monoNanos(time.Now()))
+			continue
 		case <-timer.C:
 			runner.mu.RLock()
 			paused := runner.paused
@@ -864,6 +1185,7 @@ func (s *scheduler) runTask(runner *taskRunner) {
 					if s.monitor != nil {
 						s.monitor.setPauseUntil(taskID, time.Time{})
 					}
+					s.emitTask(runner, Event{Type: EventTaskResumed})
 					continue
 				}
 
@@ -876,7 +1198,7 @@ func (s *scheduler) runTask(runner *taskRunner) {
 				runner.mu.Unlock()
 				continue
 			}
-			s.executeTask(runner)
+			s.executeTask(runner, TriggerSchedule, currentNext)
 
 			nextRun, remainingRuns, expired := s.advancePlanAfterTrigger(runner, currentNext)
 			if expired {
@@ -914,7 +1236,8 @@ func (s *scheduler) executeTaskJobOnce(task *Task, ctx context.Context) (bool, e
 }
 
 // runTaskWithRetry 带重试的任务执行包装器
-func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context) {
+// exec.scheduledAt 为零值表示手动触发，不统计调度延迟
+func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context, exec execution) {
 	runner.mu.RLock()
 	task := &Task{
 		ID:      runner.task.ID,
@@ -935,24 +1258,84 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 	}
 
 	startTime := time.Now()
+	executionID, trigger, scheduledAt := exec.id, exec.trigger, exec.scheduledAt
+	labels := task.Options.Labels
 	finalSuccess := false
 	actualRetries := 0
 	var lastErr error
 
-	if s.eventHook != nil {
-		s.eventHook(Event{TaskID: task.ID, Start: startTime})
+	var (
+		sample *resourceSample
+		busy   time.Duration // 各次尝试的执行耗时合计
+	)
+	if s.resources != nil {
+		sample = s.resources.begin(runner.executions.Add(1))
 	}
 
+	tracer := s.tracer
+	if tracer == nil {
+		tracer = NoOpTracer{}
+	}
+	execAttrs := executionSpanAttrs(task.ID, executionID, trigger, task.Options.Labels)
+	execCtx, endExecSpan := tracer.StartSpan(baseCtx, SpanExecution, execAttrs)
+
+	var lag time.Duration
+	if !scheduledAt.IsZero() {
+		lag = max(startTime.Sub(scheduledAt), 0)
+		if s.monitor != nil {
+			s.monitor.recordLag(task.ID, lag)
+		}
+		if s.lagWarnThreshold > 0 && lag > s.lagWarnThreshold {
+			logAttrs(baseCtx, s.logger, slog.LevelWarn, "task started late",
+				slog.String(LogKeyTaskID, task.ID),
+				slog.String(LogKeyExecutionID, executionID),
+				slog.Time("scheduled_at", scheduledAt),
+				slog.Duration("lag", lag),
+				slog.Duration("threshold", s.lagWarnThreshold))
+		}
+	}
+
+	s.emit(Event{
+		Type:          EventTaskStarted,
+		Time:          startTime,
+		TaskID:        task.ID,
+		Labels:        cloneLabels(labels),
+		ExecutionID:   executionID,
+		Trigger:       trigger,
+		Start:         startTime,
+		ScheduledTime: scheduledAt,
+		Lag:           lag,
+	})
+
 	defer func() {
 		endTime := time.Now()
+		if finalSuccess {
+			endExecSpan(nil)
+		} else if lastErr != nil {
+			endExecSpan(lastErr)
+		} else {
+			endExecSpan(fmt.Errorf("task failed after %d retries", actualRetries))
+		}
+
+		if s.leaks != nil {
+			s.leaks.watch(task.ID, executionID)
+		}
 
 		// 清理重试状态，避免影响下次调度
 		runner.retry.mu.Lock()
 		runner.retry.attempts = 0
 		runner.retry.mu.Unlock()
 
+		var resources *history.ResourceUsage
+		if s.resources != nil {
+			resources = s.resources.end(sample, busy)
+		}
+
 		// 记录最终统计
 		duration := endTime.Sub(startTime)
+		if s.monitor != nil && resources != nil {
+			s.monitor.recordResources(task.ID, resources)
+		}
 		if s.monitor != nil {
 			lastError := ""
 			if lastErr != nil {
@@ -961,21 +1344,26 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 			s.monitor.recordExecutionResult(task.ID, endTime, duration, finalSuccess, actualRetries, lastError)
 		}
 
-		if s.eventHook != nil {
-			errMsg := ""
-			if lastErr != nil {
-				errMsg = lastErr.Error()
-			}
-			s.eventHook(Event{
-				TaskID:   task.ID,
-				Start:    startTime,
-				End:      endTime,
-				Success:  finalSuccess,
-				Error:    errMsg,
-				Retries:  actualRetries,
-				Duration: duration,
-			})
+		errMsg := ""
+		if lastErr != nil {
+			errMsg = lastErr.Error()
 		}
+		s.emit(Event{
+			Type:          EventTaskFinished,
+			Time:          endTime,
+			TaskID:        task.ID,
+			Labels:        cloneLabels(labels),
+			ExecutionID:   executionID,
+			Trigger:       trigger,
+			Start:         startTime,
+			End:           endTime,
+			Success:       finalSuccess,
+			Error:         errMsg,
+			Retries:       actualRetries,
+			Duration:      duration,
+			ScheduledTime: scheduledAt,
+			Lag:           lag,
+		})
 
 		// 记录执行历史（如果启用）
 		if s.recorder != nil {
@@ -983,7 +1371,26 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 			if !finalSuccess && recordErr == nil {
 				recordErr = fmt.Errorf("task failed after %d retries", actualRetries)
 			}
-			s.recorder.Record(task.ID, startTime, endTime, finalSuccess, actualRetries, recordErr)
+			if recorder, ok := s.recorder.(history.ExecutionRecorder); ok {
+				record := &history.ExecutionRecord{
+					ID:            executionID,
+					TaskID:        task.ID,
+					StartTime:     startTime,
+					EndTime:       endTime,
+					Duration:      duration,
+					Success:       finalSuccess,
+					RetryCount:    actualRetries,
+					ScheduledTime: scheduledAt,
+					Lag:           lag,
+					Resources:     resources,
+				}
+				if recordErr != nil {
+					record.Error = recordErr.Error()
+				}
+				recorder.RecordExecution(record)
+			} else {
+				s.recorder.Record(task.ID, startTime, endTime, finalSuccess, actualRetries, recordErr)
+			}
 		}
 	}()
 
@@ -991,9 +1398,10 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 		// 若基础上下文已取消，则直接退出
 		select {
 		case <-baseCtx.Done():
-			if s.logger != nil {
-				s.logger.Warnf("Task %s cancelled before attempt %d", task.ID, attempt+1)
-			}
+			logAttrs(baseCtx, s.logger, slog.LevelWarn, "task cancelled before attempt",
+				slog.String(LogKeyTaskID, task.ID),
+				slog.String(LogKeyExecutionID, executionID),
+				slog.Int(LogKeyAttempt, attempt+1))
 			finalSuccess = false
 			actualRetries = attempt
 			lastErr = baseCtx.Err()
@@ -1002,14 +1410,26 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 		}
 
 		// 为当前尝试创建独立的超时上下文，避免前一次的取消影响后续重试
-		attemptCtx := baseCtx
+		attemptAttrs := maps.Clone(execAttrs)
+		attemptAttrs[AttrAttempt] = attempt + 1
+		attemptCtx, endAttemptSpan := tracer.StartSpan(execCtx, SpanAttempt, attemptAttrs)
 		cancelAttempt := func() {}
 		if timeout > 0 {
-			attemptCtx, cancelAttempt = context.WithTimeout(baseCtx, timeout)
+			attemptCtx, cancelAttempt = context.WithTimeout(attemptCtx, timeout)
 		}
 
+		attemptCtx = withExecutionLog(attemptCtx, executionID, attempt+1)
+		attemptStart := time.Now()
 		success, execErr := s.executeTaskJobOnce(task, attemptCtx)
+		busy += time.Since(attemptStart)
 		cancelAttempt()
+		if success {
+			endAttemptSpan(nil)
+		} else if execErr != nil {
+			endAttemptSpan(execErr)
+		} else {
+			endAttemptSpan(fmt.Errorf("task %s attempt %d failed", task.ID, attempt+1))
+		}
 
 		if success {
 			// 成功，重置重试计数与失败熔断计数
@@ -1020,9 +1440,22 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 			return
 		}
 		lastErr = execErr
+		attemptErr := ""
+		if execErr != nil {
+			attemptErr = execErr.Error()
+		}
+		s.emit(Event{
+			Type:        EventAttemptFailed,
+			TaskID:      task.ID,
+			Labels:      cloneLabels(labels),
+			ExecutionID: executionID,
+			Trigger:     trigger,
+			Attempt:     attempt + 1,
+			Error:       attemptErr,
+		})
 
 		// 连续失败熔断处理（包含最终失败场景）
-		runner.recordFailure(failWindow, failThreshold, pauseDuration, s.logger, task.ID)
+		pauseUntil, autoPaused := runner.recordFailure(failWindow, failThreshold, pauseDuration)
 		if s.monitor != nil {
 			runner.mu.RLock()
 			pausedUntil := runner.pauseUntil
@@ -1032,16 +1465,33 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 				s.monitor.setPauseUntil(task.ID, pausedUntil)
 			}
 		}
+		if autoPaused {
+			logAttrs(baseCtx, s.logger, slog.LevelWarn, "task auto-paused after consecutive failures",
+				slog.String(LogKeyTaskID, task.ID),
+				slog.String(LogKeyExecutionID, executionID),
+				slog.Int("failures", failThreshold),
+				slog.Duration("pause_duration", pauseDuration),
+				slog.Time("pause_until", pauseUntil))
+			s.emit(Event{
+				Type:        EventTaskAutoPaused,
+				TaskID:      task.ID,
+				Labels:      cloneLabels(labels),
+				ExecutionID: executionID,
+				Trigger:     trigger,
+				Error:       attemptErr,
+				PauseUntil:  pauseUntil,
+			})
+		}
 
 		// 检查是否达到最大重试次数
 		if maxRetries >= 0 && attempt == maxRetries {
-			if s.logger != nil {
-				if lastErr != nil {
-					s.logger.Errorf("Task %s failed after %d retries: %v", task.ID, attempt, lastErr)
-				} else {
-					s.logger.Errorf("Task %s failed after %d retries", task.ID, attempt)
-				}
-			}
+			logAttrs(baseCtx, s.logger, slog.LevelError, "task failed after retries",
+				slog.String(LogKeyTaskID, task.ID),
+				slog.String(LogKeyExecutionID, executionID),
+				slog.Int(LogKeyAttempt, attempt+1),
+				slog.Int("retries", attempt),
+				slog.Duration(LogKeyDuration, time.Since(startTime)),
+				errorAttr(lastErr))
 			finalSuccess = false
 			actualRetries = attempt
 			return
@@ -1052,15 +1502,24 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 		runner.retry.attempts = attempt + 1
 		runner.retry.mu.Unlock()
 
-		if s.logger != nil {
-			if lastErr != nil {
-				s.logger.Warnf("Task %s failed, retrying %d/%d after %v: %v",
-					task.ID, attempt+1, maxRetries, retryInterval, lastErr)
-			} else {
-				s.logger.Warnf("Task %s failed, retrying %d/%d after %v",
-					task.ID, attempt+1, maxRetries, retryInterval)
-			}
-		}
+		s.emit(Event{
+			Type:        EventRetryScheduled,
+			TaskID:      task.ID,
+			Labels:      cloneLabels(labels),
+			ExecutionID: executionID,
+			Trigger:     trigger,
+			Attempt:     attempt + 2,
+			Error:       attemptErr,
+			RetryDelay:  retryInterval,
+		})
+
+		logAttrs(baseCtx, s.logger, slog.LevelWarn, "task attempt failed, retrying",
+			slog.String(LogKeyTaskID, task.ID),
+			slog.String(LogKeyExecutionID, executionID),
+			slog.Int(LogKeyAttempt, attempt+1),
+			slog.Int("max_retries", maxRetries),
+			slog.Duration("retry_delay", retryInterval),
+			errorAttr(lastErr))
 
 		// 等待重试间隔（检查上下文取消）
 		if retryInterval > 0 {
@@ -1088,8 +1547,37 @@ func (s *scheduler) runTaskWithRetry(runner *taskRunner, baseCtx context.Context
 	}
 }
 
-// executeTask 执行任务
-func (s *scheduler) executeTask(runner *taskRunner) {
+// execution 单次执行的触发信息
+type execution struct {
+	id          string        // 执行 ID，触发时生成，被跳过的触发同样带有 ID
+	trigger     TriggerSource // 触发来源
+	scheduledAt time.Time     // 计划触发时间，手动触发时为零值
+}
+
+// executionLogKey 在尝试上下文中携带执行 ID 与尝试序号，供执行器输出日志
+type executionLogKey struct{}
+
+type executionLog struct {
+	id      string
+	attempt int
+}
+
+// withExecutionLog 将执行 ID 与尝试序号附加到上下文
+func withExecutionLog(ctx context.Context, executionID string, attempt int) context.Context {
+	return context.WithValue(ctx, executionLogKey{}, executionLog{id: executionID, attempt: attempt})
+}
+
+// executionLogAttrs 返回执行器日志的公共属性，上下文未携带执行信息时仅含任务 ID
+func executionLogAttrs(ctx context.Context, taskID string) []slog.Attr {
+	attrs := []slog.Attr{slog.String(LogKeyTaskID, taskID)}
+	if info, ok := ctx.Value(executionLogKey{}).(executionLog); ok {
+		attrs = append(attrs, slog.String(LogKeyExecutionID, info.id), slog.Int(LogKeyAttempt, info.attempt))
+	}
+	return slices.Clip(attrs) // 各调用方各自 append，避免共享底层数组
+}
+
+// executeTask 执行任务，scheduledAt 为计划触发时间（手动触发时为零值）
+func (s *scheduler) executeTask(runner *taskRunner, trigger TriggerSource, scheduledAt time.Time) {
 	// 并发控制逻辑
 	runner.mu.RLock()
 	taskID := runner.task.ID
@@ -1097,13 +1585,18 @@ func (s *scheduler) executeTask(runner *taskRunner) {
 	async := runner.task.Options.Async
 	runner.mu.RUnlock()
 
+	exec := execution{id: newExecutionID(taskID, time.Now()), trigger: trigger, scheduledAt: scheduledAt}
+
 	defer func() {
 		if r := recover(); r != nil {
 			stack := debug.Stack()
 			if s.panicHandler != nil {
 				s.panicHandler.HandlePanic(taskID, r, stack)
-			} else if s.logger != nil {
-				s.logger.Errorf("Task %s panicked: %v", taskID, r)
+			} else {
+				logAttrs(context.Background(), s.logger, slog.LevelError, "task panicked",
+					slog.String(LogKeyTaskID, taskID),
+					slog.String(LogKeyExecutionID, exec.id),
+					slog.Any("panic", r))
 			}
 		}
 	}()
@@ -1128,12 +1621,19 @@ func (s *scheduler) executeTask(runner *taskRunner) {
 			}
 		default:
 			// 超过并发限制，立即放弃任务
-			if s.logger != nil {
-				s.logger.Warnf("Task %s skipped due to concurrency limit (%d)", taskID, maxConcurrent)
-			}
+			logAttrs(context.Background(), s.logger, slog.LevelWarn, "task skipped due to concurrency limit",
+				slog.String(LogKeyTaskID, taskID),
+				slog.String(LogKeyExecutionID, exec.id),
+				slog.Int("max_concurrent", maxConcurrent))
 			if s.monitor != nil {
 				s.monitor.recordSkip(taskID)
 			}
+			s.emitTask(runner, Event{
+				Type:          EventTaskSkipped,
+				ExecutionID:   exec.id,
+				Trigger:       exec.trigger,
+				ScheduledTime: exec.scheduledAt,
+			})
 			return
 		}
 	}
@@ -1165,8 +1665,16 @@ func (s *scheduler) executeTask(runner *taskRunner) {
 			s.execWG.Done()
 		}()
 
-		// 使用重试包装器（关键修改）
-		s.runTaskWithRetry(runner, taskCtx)
+		if !async && trigger == TriggerSchedule {
+			// 调度循环同步执行任务期间耗时由任务决定，不视为停滞
+			runner.dueAt.Store(0)
+			defer func() { runner.dueAt.Store(monoNanos(time.Now())) }()
+		}
+
+		// 使用重试包装器，执行协程带有 task_id / trigger pprof 标签
+		runLabeled(taskCtx, taskID, exec.trigger, func(ctx context.Context) {
+			s.runTaskWithRetry(runner, ctx, exec)
+		})
 	}
 
 	if async {
@@ -1179,7 +1687,7 @@ func (s *scheduler) executeTask(runner *taskRunner) {
 // executeFunc 定义通用执行函数类型，用于统一处理任务执行逻辑
 type executeFunc func() error
 
-// executeWithTimeout 通用执行器，提供 goroutine 监控、超时控制和 panic 恢复
+// executeWithTimeout 通用执行器，提供超时控制、panic 恢复，启用泄漏检测时为执行协程设置标签
 // 参数:
 //   - taskID: 任务ID，用于日志记录
 //   - ctx: 上下文，控制超时和取消
@@ -1191,13 +1699,20 @@ type executeFunc func() error
 //   - success: 是否成功执行
 //   - err: 执行错误信息
 func (s *scheduler) executeWithTimeout(taskID string, ctx context.Context, fn executeFunc, panicHandler PanicHandler, logger Logger) (bool, error) {
-	// 记录执行前的 goroutine 数量
-	goroutinesBefore := runtime.NumGoroutine()
+	start := time.Now()
+	attrs := executionLogAttrs(ctx, taskID)
 
 	done := make(chan error, 1)
 
 	// 在新 goroutine 中执行任务
 	go func() {
+		if s.leaks != nil {
+			if info, ok := ctx.Value(executionLogKey{}).(executionLog); ok {
+				// 任务派生的协程继承标签，执行结束后据此统计残留协程
+				pprof.SetGoroutineLabels(s.leaks.labelContext(ctx, taskID, info.id))
+			}
+		}
+
 		var err error
 		// panic 恢复处理
 		defer func() {
@@ -1205,8 +1720,9 @@ func (s *scheduler) executeWithTimeout(taskID string, ctx context.Context, fn ex
 				stack := debug.Stack()
 				if panicHandler != nil {
 					panicHandler.HandlePanic(taskID, r, stack)
-				} else if logger != nil {
-					logger.Errorf("Task %s panicked: %v\n%s", taskID, r, string(stack))
+				} else {
+					logAttrs(ctx, logger, slog.LevelError, "task panicked",
+						append(attrs, slog.Any("panic", r), slog.String("stack", string(stack)))...)
 				}
 				err = fmt.Errorf("panic recovered in task %s: %v", taskID, r)
 			}
@@ -1220,21 +1736,9 @@ func (s *scheduler) executeWithTimeout(taskID string, ctx context.Context, fn ex
 	// 等待任务完成或超时
 	select {
 	case err := <-done:
-		// 记录执行后的 goroutine 数量
-		goroutinesAfter := runtime.NumGoroutine()
-
-		// 如果 goroutine 数量增加，记录警告（可能存在泄漏）
-		if goroutinesAfter > goroutinesBefore+1 {
-			if logger != nil {
-				logger.Warnf("Task %s may have goroutine leak: before=%d, after=%d",
-					taskID, goroutinesBefore, goroutinesAfter)
-			}
-		}
-
 		if err != nil {
-			if logger != nil {
-				logger.Errorf("Task %s failed: %v", taskID, err)
-			}
+			logAttrs(ctx, logger, slog.LevelError, "task attempt failed",
+				append(attrs, slog.Duration(LogKeyDuration, time.Since(start)), errorAttr(err))...)
 			return false, err
 		}
 		return true, nil
@@ -1242,9 +1746,8 @@ func (s *scheduler) executeWithTimeout(taskID string, ctx context.Context, fn ex
 	case <-ctx.Done():
 		// 超时或取消
 		timeoutErr := fmt.Errorf("task %s timed out: %w", taskID, ctx.Err())
-		if logger != nil {
-			logger.Errorf("Task %s timed out: %v", taskID, ctx.Err())
-		}
+		logAttrs(ctx, logger, slog.LevelError, "task attempt timed out",
+			append(attrs, slog.Duration(LogKeyDuration, time.Since(start)), errorAttr(ctx.Err()))...)
 		return false, timeoutErr
 	}
 }
@@ -0,0 +1,90 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitForCount(t *testing.T, counter *atomic.Int32, want int32, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for counter.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d runs, got %d", want, counter.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestRebootRunsOnEachStart 测试 @reboot 在每次 Start 时触发一次，包括 Stop 后重新启动
+func TestRebootRunsOnEachStart(t *testing.T) {
+	var runs atomic.Int32
+	c := New(WithLogger(&NoOpLogger{}))
+	if err := c.Schedule("boot", "@reboot", func(ctx context.Context) { runs.Add(1) }); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	waitForCount(t, &runs, 1, time.Second)
+	time.Sleep(200 * time.Millisecond)
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("expected exactly one run per start, got %d", got)
+	}
+	if info, ok := c.GetTask("boot"); !ok || !info.NextRun.IsZero() {
+		t.Fatalf("expected task to stay registered with zero NextRun, got %+v", info)
+	}
+
+	c.Stop()
+	if err := c.Start(); err != nil {
+		t.Fatalf("restart failed: %v", err)
+	}
+	defer c.Stop()
+	waitForCount(t, &runs, 2, time.Second)
+	time.Sleep(200 * time.Millisecond)
+	if got := runs.Load(); got != 2 {
+		t.Fatalf("expected two runs after restart, got %d", got)
+	}
+}
+
+// TestStartDescriptorDelay 测试 @start 带延迟触发
+func TestStartDescriptorDelay(t *testing.T) {
+	var runs atomic.Int32
+	c := New(WithLogger(&NoOpLogger{}))
+	if err := c.Schedule("warmup", "@start 300ms", func(ctx context.Context) { runs.Add(1) }); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer c.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	if got := runs.Load(); got != 0 {
+		t.Fatalf("expected no run before delay, got %d", got)
+	}
+	waitForCount(t, &runs, 1, time.Second)
+}
+
+// TestRebootAddedWhileRunning 测试调度器运行中添加的 @reboot 任务同样触发一次
+func TestRebootAddedWhileRunning(t *testing.T) {
+	var runs atomic.Int32
+	c := New(WithLogger(&NoOpLogger{}))
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer c.Stop()
+
+	if err := c.Schedule("late", "@reboot", func(ctx context.Context) { runs.Add(1) }); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	waitForCount(t, &runs, 1, time.Second)
+	time.Sleep(200 * time.Millisecond)
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("expected exactly one run, got %d", got)
+	}
+}
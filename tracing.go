@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 执行链路的 Span 名称
+const (
+	SpanExecution = "cron.execution" // 一次完整执行（含全部重试）
+	SpanAttempt   = "cron.attempt"   // 单次尝试，父 Span 为 SpanExecution
+)
+
+// Span 属性键，任务标签以 AttrLabelPrefix + 标签名 的形式附加
+const (
+	AttrTaskID      = "cron.task_id"
+	AttrExecutionID = "cron.execution_id"
+	AttrAttempt     = "cron.attempt"
+	AttrTrigger     = "cron.trigger"
+	AttrLabelPrefix = "cron.label."
+)
+
+// TriggerSource 执行的触发来源
+type TriggerSource string
+
+const (
+	TriggerSchedule TriggerSource = "schedule" // 按计划触发
+	TriggerCatchUp  TriggerSource = "catchup"  // MisfireCatchUp 策略补跑
+	TriggerManual   TriggerSource = "manual"   // RunNow 手动触发
+)
+
+// Tracer 追踪接口，风格与 OpenTelemetry 一致，便于桥接到实际的追踪系统。
+// StartSpan 返回携带新 Span 的上下文与结束函数，结束函数接收本段执行的错误（成功为 nil）。
+// 任务处理函数收到的 ctx 派生自尝试 Span 的上下文，可在任务内继续创建子 Span。
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, attrs map[string]any) (context.Context, func(err error))
+}
+
+// NoOpTracer 空追踪实现，不记录任何内容
+type NoOpTracer struct{}
+
+// StartSpan 空实现，原样返回上下文
+func (NoOpTracer) StartSpan(ctx context.Context, name string, attrs map[string]any) (context.Context, func(err error)) {
+	return ctx, func(error) {}
+}
+
+// RecordedSpan 内存追踪器记录的 Span
+type RecordedSpan struct {
+	ID         uint64
+	ParentID   uint64 // 父 Span ID，0 表示根 Span
+	Name       string
+	Attributes map[string]any
+	Start      time.Time
+	End        time.Time // 未结束时为零值
+	Err        error
+}
+
+// RecordingTracer 内存追踪实现，按开始顺序记录全部 Span，主要用于测试
+type RecordingTracer struct {
+	mu     sync.Mutex
+	spans  []*RecordedSpan
+	nextID atomic.Uint64
+}
+
+// NewRecordingTracer 创建内存追踪器
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+type recordingSpanKey struct{}
+
+// StartSpan 记录 Span 开始，并将 Span ID 写入上下文用于关联子 Span
+func (t *RecordingTracer) StartSpan(ctx context.Context, name string, attrs map[string]any) (context.Context, func(err error)) {
+	span := &RecordedSpan{
+		ID:         t.nextID.Add(1),
+		Name:       name,
+		Attributes: maps.Clone(attrs),
+		Start:      time.Now(),
+	}
+	if parent, ok := ctx.Value(recordingSpanKey{}).(uint64); ok {
+		span.ParentID = parent
+	}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	var once sync.Once
+	return context.WithValue(ctx, recordingSpanKey{}, span.ID), func(err error) {
+		once.Do(func() {
+			t.mu.Lock()
+			span.End = time.Now()
+			span.Err = err
+			t.mu.Unlock()
+		})
+	}
+}
+
+// Spans 返回已记录 Span 的快照
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]RecordedSpan, len(t.spans))
+	for i, span := range t.spans {
+		spans[i] = *span
+		spans[i].Attributes = maps.Clone(span.Attributes)
+	}
+	return spans
+}
+
+// Reset 清空已记录的 Span
+func (t *RecordingTracer) Reset() {
+	t.mu.Lock()
+	t.spans = nil
+	t.mu.Unlock()
+}
+
+// RecordedSpanIDFromContext 返回上下文中由 RecordingTracer 创建的当前 Span ID
+func RecordedSpanIDFromContext(ctx context.Context) (uint64, bool) {
+	id, ok := ctx.Value(recordingSpanKey{}).(uint64)
+	return id, ok
+}
+
+// executionSpanAttrs 构造执行 Span 的属性
+func executionSpanAttrs(taskID, executionID string, trigger TriggerSource, labels map[string]string) map[string]any {
+	attrs := make(map[string]any, 3+len(labels))
+	attrs[AttrTaskID] = taskID
+	attrs[AttrExecutionID] = executionID
+	attrs[AttrTrigger] = string(trigger)
+	for key, value := range labels {
+		attrs[AttrLabelPrefix+key] = value
+	}
+	return attrs
+}
+
+// newExecutionID 生成执行 ID，格式与历史记录 ID 一致（任务ID_触发时间纳秒）
+func newExecutionID(taskID string, start time.Time) string {
+	return taskID + "_" + strconv.FormatInt(start.UnixNano(), 10)
+}
@@ -0,0 +1,134 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyJob 前 failures 次执行返回错误，并记录每次执行收到的 Span ID
+type flakyJob struct {
+	failures int
+	calls    int
+	spanIDs  []uint64
+}
+
+func (j *flakyJob) Name() string { return "flaky" }
+
+func (j *flakyJob) Run(ctx context.Context) error {
+	id, _ := RecordedSpanIDFromContext(ctx)
+	j.spanIDs = append(j.spanIDs, id)
+	j.calls++
+	if j.calls <= j.failures {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+// TestTracerExecutionAndAttemptSpans 测试执行与每次尝试各生成一个 Span，属性完整且上下文传递到任务
+func TestTracerExecutionAndAttemptSpans(t *testing.T) {
+	tracer := NewRecordingTracer()
+	s := newScheduler()
+	s.logger = &NoOpLogger{}
+	s.tracer = tracer
+	var events []Event
+	s.eventHook = func(e Event) {
+		if e.Type == EventTaskStarted || e.Type == EventTaskFinished {
+			events = append(events, e)
+		}
+	}
+
+	job := &flakyJob{failures: 1}
+	task := &Task{ID: "traced", Schedule: "0 0 1 1 *", Job: job, Options: JobOptions{
+		MaxRetries: 2,
+		Labels:     map[string]string{"team": "ops"},
+	}}
+	if err := s.addTask(task); err != nil {
+		t.Fatalf("add task failed: %v", err)
+	}
+	s.executeTask(s.tasks[task.ID], TriggerSchedule, time.Now())
+
+	spans := tracer.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 1 execution + 2 attempt spans, got %d: %+v", len(spans), spans)
+	}
+	exec, first, second := spans[0], spans[1], spans[2]
+	if exec.Name != SpanExecution || first.Name != SpanAttempt || second.Name != SpanAttempt {
+		t.Fatalf("unexpected span names: %s, %s, %s", exec.Name, first.Name, second.Name)
+	}
+	if exec.ParentID != 0 || first.ParentID != exec.ID || second.ParentID != exec.ID {
+		t.Fatalf("attempt spans should be children of the execution span: %+v", spans)
+	}
+	if exec.End.IsZero() || exec.Err != nil {
+		t.Fatalf("execution span should end successfully: %+v", exec)
+	}
+	if first.Err == nil || second.Err != nil {
+		t.Fatalf("unexpected attempt errors: %v, %v", first.Err, second.Err)
+	}
+
+	executionID, _ := exec.Attributes[AttrExecutionID].(string)
+	if executionID == "" || exec.Attributes[AttrTaskID] != "traced" ||
+		exec.Attributes[AttrTrigger] != "schedule" || exec.Attributes[AttrLabelPrefix+"team"] != "ops" {
+		t.Fatalf("unexpected execution attributes: %v", exec.Attributes)
+	}
+	if _, ok := exec.Attributes[AttrAttempt]; ok {
+		t.Fatal("execution span should not carry an attempt number")
+	}
+	if first.Attributes[AttrAttempt] != 1 || second.Attributes[AttrAttempt] != 2 ||
+		second.Attributes[AttrExecutionID] != executionID {
+		t.Fatalf("unexpected attempt attributes: %v, %v", first.Attributes, second.Attributes)
+	}
+
+	if len(job.spanIDs) != 2 || job.spanIDs[0] != first.ID || job.spanIDs[1] != second.ID {
+		t.Fatalf("handler ctx should carry the attempt span, got %v", job.spanIDs)
+	}
+	if len(events) != 2 || events[0].ExecutionID != executionID || events[1].ExecutionID != executionID ||
+		events[1].Trigger != TriggerSchedule {
+		t.Fatalf("events should carry execution id and trigger: %+v", events)
+	}
+}
+
+// TestTracerFailedExecution 测试最终失败时执行 Span 记录最后一次错误，手动触发标记来源
+func TestTracerFailedExecution(t *testing.T) {
+	tracer := NewRecordingTracer()
+	c := New(WithLogger(&NoOpLogger{}), WithTracer(tracer))
+	defer c.Stop()
+
+	job := &flakyJob{failures: 10}
+	if err := c.ScheduleJob("always-fails", "0 0 1 1 *", job); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := c.RunNow("always-fails"); err != nil {
+		t.Fatalf("run now failed: %v", err)
+	}
+
+	spans := tracer.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Err == nil || spans[0].Err.Error() != "boom" {
+		t.Fatalf("execution span error = %v; want boom", spans[0].Err)
+	}
+	if spans[0].Attributes[AttrTrigger] != "manual" {
+		t.Fatalf("trigger = %v; want manual", spans[0].Attributes[AttrTrigger])
+	}
+
+	tracer.Reset()
+	if len(tracer.Spans()) != 0 {
+		t.Fatal("reset should clear recorded spans")
+	}
+}
+
+// TestNoOpTracer 测试空追踪器原样返回上下文
+func TestNoOpTracer(t *testing.T) {
+	ctx := context.WithValue(context.Background(), recordingSpanKey{}, uint64(7))
+	got, end := NoOpTracer{}.StartSpan(ctx, SpanExecution, nil)
+	end(nil)
+	if got != ctx {
+		t.Fatal("no-op tracer should return the original context")
+	}
+}
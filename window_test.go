@@ -0,0 +1,121 @@
+package cron
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestActiveWindowsNextRun 测试有效时段外的触发点被跳过并反映在 NextRun 中
+func TestActiveWindowsNextRun(t *testing.T) {
+	handler := func(ctx context.Context) {}
+	hour := time.Duration((time.Now().UTC().Hour()+2)%24) * time.Hour
+
+	c := New(WithLogger(&NoOpLogger{}))
+	err := c.Schedule("windowed", "0 * * * *", handler, JobOptions{
+		Location:      time.UTC,
+		ActiveWindows: []ActiveWindow{{Start: hour, End: hour + time.Hour}},
+	})
+	if err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+
+	info, ok := c.GetTask("windowed")
+	if !ok {
+		t.Fatal("task not found")
+	}
+	next := info.NextRun.UTC()
+	if time.Duration(next.Hour())*time.Hour != hour || next.Minute() != 0 {
+		t.Fatalf("NextRun = %v; want %02d:00 UTC", next, int(hour.Hours()))
+	}
+}
+
+// TestActiveWindowsConstantDelay 测试 @every 与有效时段、StartAt 组合时按间隔推算并落入时段
+func TestActiveWindowsConstantDelay(t *testing.T) {
+	s := newSchedulerWithContext(context.Background())
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return base.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	opts := JobOptions{
+		Location:      time.UTC,
+		ActiveWindows: []ActiveWindow{{Start: 8 * time.Hour, End: 18 * time.Hour}},
+	}
+	schedule, err := s.parseTaskSchedule("@every 1h", opts, nil)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		startAt time.Time
+		now     time.Time
+		want    time.Time
+	}{
+		{"start at in future", at(10, 0), at(9, 0), at(10, 0)},
+		{"start at in past aligns to step", at(8, 30), at(12, 10), at(12, 30)},
+		{"step outside window waits for open", at(8, 30), at(18, 10), at(24+8, 0)},
+		{"no start at runs immediately", time.Time{}, at(9, 15), at(9, 15)},
+		{"no start at outside window", time.Time{}, at(20, 0), at(24+8, 0)},
+	}
+	for _, tt := range tests {
+		opts.StartAt = tt.startAt
+		next, _, expired := planInitialState(schedule, opts, tt.now)
+		if expired || !next.Equal(tt.want) {
+			t.Fatalf("%s: planInitialState = %v (expired=%v); want %v", tt.name, next, expired, tt.want)
+		}
+		next, expired = recomputeNextRun(schedule, tt.startAt, -1, tt.now)
+		if expired || !next.Equal(tt.want) {
+			t.Fatalf("%s: recomputeNextRun = %v (expired=%v); want %v", tt.name, next, expired, tt.want)
+		}
+	}
+}
+
+// TestEndAtExpiresTask 测试到达截止时间后任务自动移除
+func TestEndAtExpiresTask(t *testing.T) {
+	handler := func(ctx context.Context) {}
+
+	c := New(WithLogger(&NoOpLogger{}))
+	if err := c.Schedule("ending", "* * * * * *", handler, JobOptions{EndAt: time.Now().Add(1500 * time.Millisecond)}); err != nil {
+		t.Fatalf("schedule failed: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	defer c.Stop()
+
+	deadline := time.Now().Add(4 * time.Second)
+	for slices.Contains(c.List(), "ending") {
+		if time.Now().After(deadline) {
+			t.Fatal("task was not removed after EndAt")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// TestEndAtAndWindowValidation 测试截止时间与有效时段的参数校验
+func TestEndAtAndWindowValidation(t *testing.T) {
+	handler := func(ctx context.Context) {}
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		opts JobOptions
+	}{
+		{"截止时间已过", JobOptions{EndAt: now.Add(-time.Minute)}},
+		{"截止时间早于开始时间", JobOptions{StartAt: now.Add(time.Hour), EndAt: now.Add(time.Minute)}},
+		{"空时段", JobOptions{ActiveWindows: []ActiveWindow{{Start: time.Hour, End: time.Hour}}}},
+		{"时段越界", JobOptions{ActiveWindows: []ActiveWindow{{Start: time.Hour, End: 25 * time.Hour}}}},
+		{"非法星期", JobOptions{ActiveWindows: []ActiveWindow{{Start: time.Hour, End: 2 * time.Hour, Weekdays: []time.Weekday{7}}}}},
+	}
+
+	c := New(WithLogger(&NoOpLogger{}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.Schedule("invalid", "* * * * *", handler, tt.opts); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}